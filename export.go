@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	pb "github.com/awe76/sagawf/proto"
+	"github.com/awe76/sagawf/workflow"
+
+	"go-micro.dev/v4"
+)
+
+// runExport prints the graph of the definition file or asks the running service for it
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	id := fs.String("id", "", "Id of the workflow rendered with its state")
+	name := fs.String("definition", "", "Name of the registered workflow definition")
+	version := fs.Int("version", 0, "Version of the registered workflow definition, the latest if 0")
+	file := fs.String("file", "", "Workflow definition file rendered without the running service")
+	format := fs.String("format", workflow.FORMAT_DOT, "Output format: dot or mermaid")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *file != "" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		w, err := workflow.LoadDefinition(f)
+		if err != nil {
+			return err
+		}
+
+		graph, err := workflow.Export(w, *format, nil)
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(graph)
+		return nil
+	}
+
+	if *id == "" && *name == "" {
+		return fmt.Errorf("one of -id, -definition or -file is required")
+	}
+
+	srv := micro.NewService()
+	sagawf := pb.NewSagawfService(service, srv.Client())
+
+	rsp, err := sagawf.ExportWorkflow(context.Background(), &pb.ExportRequest{
+		Id:                *id,
+		DefinitionName:    *name,
+		DefinitionVersion: int32(*version),
+		Format:            *format,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(rsp.Graph)
+	return nil
+}
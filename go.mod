@@ -8,15 +8,18 @@ require (
 	github.com/awe76/sagaproc v0.0.0-20211227164624-9303312bc6d1
 	github.com/cpuguy83/go-md2man/v2 v2.0.1 // indirect
 	github.com/fsnotify/fsnotify v1.5.1 // indirect
-	github.com/google/uuid v1.3.0 // indirect
+	github.com/google/uuid v1.3.0
 	github.com/kevinburke/ssh_config v1.1.0 // indirect
 	github.com/sergi/go-diff v1.2.0 // indirect
 	github.com/stretchr/testify v1.7.0
+	github.com/urfave/cli/v2 v2.3.0
 	github.com/xanzy/ssh-agent v0.3.1 // indirect
 	go-micro.dev/v4 v4.5.0
+	go.etcd.io/bbolt v1.3.6
 	golang.org/x/net v0.0.0-20211015210444-4f30a5c0130f // indirect
 	golang.org/x/text v0.3.7 // indirect
 	google.golang.org/protobuf v1.27.1
+	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
 )
 
 // This can be removed once etcd becomes go gettable, version 3.4 and 3.5 is not,
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/awe76/sagawf/workflow"
+	"go-micro.dev/v4/broker"
+)
+
+type Options struct {
+	Cache workflow.Cache
+	// UUID enables random workflow ids instead of the sequential counter
+	UUID bool
+	// TimerInterval is the period of operation and workflow timeouts checks
+	TimerInterval time.Duration
+	// Definitions is the directory of workflow definition files registered at startup
+	Definitions string
+	// Executors run the operations of their types in addition to the built-in rpc and http executors
+	Executors map[string]workflow.Executor
+	// Broker carries the workflow events, the go-micro default broker is used if it is not set
+	Broker broker.Broker
+	// Namespace prefixes the topics so coordinators of different environments can share the broker
+	Namespace string
+	// Queue is the group of the replicas sharing the operation and workflow start events
+	Queue string
+	// IdempotencyRetention is the period the idempotency key of the request is mapped to its workflow
+	IdempotencyRetention time.Duration
+	// RecoveryGrace is the period the operation started before the restart is expected to be completed in,
+	// the operations in progress longer than it are started again
+	RecoveryGrace time.Duration
+}
+
+type Option func(o *Options)
+
+func Cache(c workflow.Cache) Option {
+	return func(o *Options) {
+		o.Cache = c
+	}
+}
+
+func UUID() Option {
+	return func(o *Options) {
+		o.UUID = true
+	}
+}
+
+func TimerInterval(d time.Duration) Option {
+	return func(o *Options) {
+		o.TimerInterval = d
+	}
+}
+
+func Definitions(dir string) Option {
+	return func(o *Options) {
+		o.Definitions = dir
+	}
+}
+
+func Executor(name string, e workflow.Executor) Option {
+	return func(o *Options) {
+		if o.Executors == nil {
+			o.Executors = make(map[string]workflow.Executor)
+		}
+
+		o.Executors[name] = e
+	}
+}
+
+func Broker(b broker.Broker) Option {
+	return func(o *Options) {
+		o.Broker = b
+	}
+}
+
+func Namespace(namespace string) Option {
+	return func(o *Options) {
+		o.Namespace = namespace
+	}
+}
+
+func Queue(name string) Option {
+	return func(o *Options) {
+		o.Queue = name
+	}
+}
+
+func IdempotencyRetention(d time.Duration) Option {
+	return func(o *Options) {
+		o.IdempotencyRetention = d
+	}
+}
+
+func RecoveryGrace(d time.Duration) Option {
+	return func(o *Options) {
+		o.RecoveryGrace = d
+	}
+}
+
+func newOptions(opts ...Option) Options {
+	options := Options{}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	if options.Cache == nil {
+		options.Cache = workflow.NewCache()
+	}
+
+	if options.Broker == nil {
+		options.Broker = broker.DefaultBroker
+	}
+
+	if options.Queue == "" {
+		options.Queue = "sagawf"
+	}
+
+	if options.IdempotencyRetention == 0 {
+		options.IdempotencyRetention = 24 * time.Hour
+	}
+
+	if options.TimerInterval == 0 {
+		options.TimerInterval = time.Second
+	}
+
+	return options
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"sync"
+
+	"github.com/awe76/sagawf/workflow"
+)
+
+type registry struct {
+	mu       sync.Mutex
+	handlers map[string][]chan workflow.WorkflowPayload
+}
+
+func newRegistry() *registry {
+	return &registry{
+		handlers: make(map[string][]chan workflow.WorkflowPayload),
+	}
+}
+
+func (r *registry) register(id string) chan workflow.WorkflowPayload {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// the channel is buffered so the result is never blocked by the caller
+	result := make(chan workflow.WorkflowPayload, 1)
+	// retried requests with the same idempotency key wait for the same workflow
+	r.handlers[id] = append(r.handlers[id], result)
+
+	return result
+}
+
+func (r *registry) unregister(id string, ch chan workflow.WorkflowPayload) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	handlers := []chan workflow.WorkflowPayload{}
+	for _, handler := range r.handlers[id] {
+		if handler != ch {
+			handlers = append(handlers, handler)
+		}
+	}
+
+	if len(handlers) == 0 {
+		delete(r.handlers, id)
+	} else {
+		r.handlers[id] = handlers
+	}
+}
+
+func (r *registry) notify(w workflow.WorkflowPayload) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, ch := range r.handlers[w.ID] {
+		ch <- w
+	}
+	delete(r.handlers, w.ID)
+}
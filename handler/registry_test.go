@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/awe76/sagawf/workflow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	r := newRegistry()
+
+	// the callers of a workflow register and leave while its result is delivered
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		id := strconv.Itoa(i % 10)
+		isLeft := i%2 == 0
+
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+
+			ch := r.register(id)
+			if isLeft {
+				r.unregister(id, ch)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+
+			r.notify(workflow.WorkflowPayload{ID: id})
+		}()
+	}
+	wg.Wait()
+
+	// the notified handlers are removed, so only the late ones are left
+	for i := 0; i < 10; i++ {
+		r.notify(workflow.WorkflowPayload{ID: strconv.Itoa(i)})
+	}
+	assert.Empty(t, r.handlers)
+}
+
+func TestRegistryNotify(t *testing.T) {
+	r := newRegistry()
+
+	first := r.register("1")
+	second := r.register("1")
+	left := r.register("1")
+	r.unregister("1", left)
+
+	// the result is buffered so the notification is not blocked by the callers which do not read it
+	r.notify(workflow.WorkflowPayload{ID: "1", Name: "order"})
+
+	assert.Equal(t, "order", (<-first).Name)
+	assert.Equal(t, "order", (<-second).Name)
+	assert.Len(t, left, 0)
+	assert.Empty(t, r.handlers)
+}
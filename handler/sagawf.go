@@ -4,28 +4,37 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/awe76/sagawf/workflow"
 	"go-micro.dev/v4/broker"
 
-	po "github.com/awe76/sagaproc/proto"
 	pb "github.com/awe76/sagawf/proto"
+	mc "go-micro.dev/v4/cache"
 	client "go-micro.dev/v4/client"
+	"go-micro.dev/v4/errors"
 )
 
 type Sagawf struct {
-	cache    workflow.Cache
-	producer workflow.Producer
-	handler  map[int]chan workflow.WorkflowPayload
+	cache     workflow.Cache
+	producer  workflow.Producer
+	handler   *registry
+	watchers  *watchers
+	uuid      bool
+	retention time.Duration
+	// owner identifies the coordinator in the operation claims, its lease is renewed by the timer
+	owner string
+	lease time.Duration
+	// executors run the operations, the operation types are validated against them
+	executors workflow.Executors
 }
 
-func getWorkflowKey(id int) string {
-	return fmt.Sprintf("workflow:definition:%d", id)
-}
+func NewSagawf(c client.Client, opts ...Option) (*Sagawf, error) {
+	options := newOptions(opts...)
 
-func NewSagawf(c client.Client) (*Sagawf, error) {
-	cache := workflow.NewCache()
-	producer := workflow.NewProducer()
+	cache := options.Cache
+	producer := workflow.NewProducer(options.Broker, options.Namespace)
 	err := producer.Init()
 	if err != nil {
 		return nil, err
@@ -36,17 +45,68 @@ func NewSagawf(c client.Client) (*Sagawf, error) {
 		return nil, err
 	}
 
-	handler := make(map[int]chan workflow.WorkflowPayload)
+	owner, err := workflow.ReserveUUID()
+	if err != nil {
+		return nil, err
+	}
 
 	result := Sagawf{
-		cache:    cache,
-		producer: producer,
-		handler:  handler,
+		cache:     cache,
+		producer:  producer,
+		handler:   newRegistry(),
+		watchers:  newWatchers(),
+		uuid:      options.UUID,
+		retention: options.IdempotencyRetention,
+		owner:     owner,
+		lease:     3 * options.TimerInterval,
+	}
+
+	subscribe := func(topic string, h broker.Handler, opts ...broker.SubscribeOption) error {
+		_, err := options.Broker.Subscribe(workflow.Topic(options.Namespace, topic), h, opts...)
+		return err
+	}
+
+	// the work is shared by the replicas of the queue, the notifications are broadcast to every replica
+	work := broker.Queue(options.Queue)
+
+	executors := workflow.Executors{
+		workflow.EXECUTOR_RPC:  workflow.NewRPCExecutor(c),
+		workflow.EXECUTOR_HTTP: workflow.NewHTTPExecutor(nil),
+	}
+	for name, executor := range options.Executors {
+		executors[name] = executor
+	}
+	result.executors = executors
+
+	// the operation events are broadcast to the watchers of every replica as the operations are handled by one of them
+	watch := func(topic string, forward pb.EventType, rollback pb.EventType) error {
+		return subscribe(topic, func(p broker.Event) error {
+			var op workflow.OperationPayload
+			err := json.Unmarshal(p.Message().Body, &op)
+			if err != nil {
+				return err
+			}
+
+			return result.notifyOperation(op, forward, rollback)
+		})
 	}
 
-	proc := po.NewSagaprocService("sagaproc", c)
+	err = watch(workflow.WORKFLOW_OPERATION_START, pb.EventType_OPERATION_STARTED, pb.EventType_ROLLBACK_STARTED)
+	if err != nil {
+		return nil, err
+	}
+
+	err = watch(workflow.WORKFLOW_OPERATION_COMPLETED, pb.EventType_OPERATION_COMPLETED, pb.EventType_ROLLBACK_COMPLETED)
+	if err != nil {
+		return nil, err
+	}
+
+	err = watch(workflow.WORKFLOW_OPERATION_FAILED, pb.EventType_OPERATION_FAILED, pb.EventType_ROLLBACK_FAILED)
+	if err != nil {
+		return nil, err
+	}
 
-	_, err = broker.Subscribe(workflow.WORKFLOW_OPERATION_START, func(p broker.Event) error {
+	err = subscribe(workflow.WORKFLOW_OPERATION_START, func(p broker.Event) error {
 		var op workflow.OperationPayload
 		err := json.Unmarshal(p.Message().Body, &op)
 
@@ -54,41 +114,43 @@ func NewSagawf(c client.Client) (*Sagawf, error) {
 			return err
 		}
 
-		ctx := context.Background()
-		o := op.Operation
+		if op.IsRollback {
+			fmt.Printf("%s operation rollback is started\n", op.Operation.Name)
+		} else if op.Attempt > 1 {
+			fmt.Printf("%s operation is retried, attempt %d\n", op.Operation.Name, op.Attempt)
+		} else {
+			fmt.Printf("%s operation is started\n", op.Operation.Name)
+		}
 
-		req := po.OperationPayload{
-			Id:         int64(op.ID),
-			IsRollback: op.IsRollback,
-			Name:       op.Name,
-			Operation: &po.Operation{
-				From: o.From,
-				To:   o.To,
-				Name: o.Name,
-			},
+		// the claim keeps the recovery of the other replicas from starting the operation again
+		err = result.CreateProcessor().Claim(op, result.owner)
+		if err != nil {
+			fmt.Printf("%s operation claim is failed: %v\n", op.Operation.Name, err)
 		}
 
-		resp, err := proc.HandleOperation(ctx, &req)
+		resp, err := executors.Execute(context.Background(), op)
 
+		// an executor error fails the operation so the workflow is not blocked
 		if err != nil {
-			return err
+			fmt.Printf("%s operation execution is failed: %v\n", op.Operation.Name, err)
+			op.Payload = err.Error()
+			return producer.SendMessage(workflow.WORKFLOW_OPERATION_FAILED, op)
 		}
 
 		op.Payload = resp.Payload
 
-		// randomly complete or fault the operation
 		if resp.IsFailed {
 			return producer.SendMessage(workflow.WORKFLOW_OPERATION_FAILED, op)
 		} else {
 			return producer.SendMessage(workflow.WORKFLOW_OPERATION_COMPLETED, op)
 		}
-	})
+	}, work)
 
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = broker.Subscribe(workflow.WORKFLOW_OPERATION_COMPLETED, func(p broker.Event) error {
+	err = subscribe(workflow.WORKFLOW_OPERATION_COMPLETED, func(p broker.Event) error {
 		var op workflow.OperationPayload
 		err := json.Unmarshal(p.Message().Body, &op)
 		if err != nil {
@@ -108,45 +170,76 @@ func NewSagawf(c client.Client) (*Sagawf, error) {
 		}
 
 		return proc.OnComplete(w, op)
-	})
+	}, work)
 
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = broker.Subscribe(workflow.WORKFLOW_OPERATION_FAILED, func(p broker.Event) error {
+	err = subscribe(workflow.WORKFLOW_OPERATION_FAILED, func(p broker.Event) error {
 		var op workflow.OperationPayload
 		err := json.Unmarshal(p.Message().Body, &op)
 		if err != nil {
 			return err
 		}
 
-		fmt.Printf("%s operation is failed\n", op.Operation.Name)
+		if op.IsRollback {
+			fmt.Printf("%s operation rollback is failed\n", op.Operation.Name)
+		} else {
+			fmt.Printf("%s operation is failed\n", op.Operation.Name)
+		}
+
 		proc := result.CreateProcessor()
 		w, err := result.GetWorkflow(op.ID)
 		if err != nil {
 			return err
 		}
 		return proc.OnFailure(w, op)
+	}, work)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = subscribe(workflow.WORKFLOW_COMPLETED, func(p broker.Event) error {
+		var w workflow.WorkflowPayload
+		err := json.Unmarshal(p.Message().Body, &w)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s %s workflow is completed\n", w.Name, w.ID)
+		fmt.Printf("workflow state: %v\n", w.Data)
+
+		err = result.notifyWorkflow(w)
+		if err != nil {
+			return err
+		}
+
+		result.handler.notify(w)
+		return nil
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = broker.Subscribe(workflow.WORKFLOW_COMPLETED, func(p broker.Event) error {
+	err = subscribe(workflow.WORKFLOW_ROLLBACKED, func(p broker.Event) error {
 		var w workflow.WorkflowPayload
 		err := json.Unmarshal(p.Message().Body, &w)
 		if err != nil {
 			return err
 		}
 
-		fmt.Printf("%s %d workflow is completed\n", w.Name, w.ID)
+		fmt.Printf("%s %s workflow is rollbacked\n", w.Name, w.ID)
 		fmt.Printf("workflow state: %v\n", w.Data)
 
-		if ch, found := handler[w.ID]; found {
-			ch <- w
+		err = result.notifyWorkflow(w)
+		if err != nil {
+			return err
 		}
+
+		result.handler.notify(w)
 		return nil
 	})
 
@@ -154,19 +247,48 @@ func NewSagawf(c client.Client) (*Sagawf, error) {
 		return nil, err
 	}
 
-	_, err = broker.Subscribe(workflow.WORKFLOW_ROLLBACKED, func(p broker.Event) error {
+	err = subscribe(workflow.WORKFLOW_START, func(p broker.Event) error {
 		var w workflow.WorkflowPayload
 		err := json.Unmarshal(p.Message().Body, &w)
 		if err != nil {
 			return err
 		}
 
-		fmt.Printf("%s %d workflow is rollbacked\n", w.Name, w.ID)
+		child, err := result.GetWorkflow(w.ID)
+		if err != nil {
+			return err
+		}
+
+		proc := result.CreateProcessor()
+		if w.IsRollback {
+			fmt.Printf("%s %s child workflow rollback is started\n", child.Name, w.ID)
+			return proc.Compensate(child, w.ID)
+		}
+
+		fmt.Printf("%s %s child workflow is started\n", child.Name, w.ID)
+		return proc.StartWorkflow(child, w.ID)
+	}, work)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = subscribe(workflow.WORKFLOW_STUCK, func(p broker.Event) error {
+		var w workflow.WorkflowPayload
+		err := json.Unmarshal(p.Message().Body, &w)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s %s workflow is stuck\n", w.Name, w.ID)
 		fmt.Printf("workflow state: %v\n", w.Data)
 
-		if ch, found := handler[w.ID]; found {
-			ch <- w
+		err = result.notifyWorkflow(w)
+		if err != nil {
+			return err
 		}
+
+		result.handler.notify(w)
 		return nil
 	})
 
@@ -174,93 +296,721 @@ func NewSagawf(c client.Client) (*Sagawf, error) {
 		return nil, err
 	}
 
+	if options.Definitions != "" {
+		definitions, err := workflow.LoadDefinitions(cache, options.Definitions, executors)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, w := range definitions {
+			fmt.Printf("%s workflow definition version %d is loaded\n", w.Name, w.Version)
+		}
+	}
+
+	err = workflow.RenewLease(cache, owner, time.Now().Add(result.lease))
+	if err != nil {
+		return nil, err
+	}
+
+	err = result.Recover(options.RecoveryGrace)
+	if err != nil {
+		return nil, err
+	}
+
+	go result.runTimers(options.TimerInterval)
+
 	return &result, nil
 }
 
+func (e *Sagawf) notifyOperation(op workflow.OperationPayload, forward pb.EventType, rollback pb.EventType) error {
+	event, err := newOperationEvent(op, forward, rollback)
+	if err != nil {
+		return err
+	}
+
+	e.watchers.notify(op.ID, event)
+	return nil
+}
+
+func (e *Sagawf) notifyWorkflow(w workflow.WorkflowPayload) error {
+	event, err := newWorkflowEvent(w)
+	if err != nil {
+		return err
+	}
+
+	e.watchers.notify(w.ID, event)
+	return nil
+}
+
 func (e *Sagawf) CreateProcessor() workflow.Processor {
 	return workflow.NewProcessor(e.cache, e.producer)
 }
 
-func (e *Sagawf) ReserveID() (int, error) {
-	id, err := workflow.ReserveID("workflow:index", e.cache)
+func (e *Sagawf) ReserveID() (string, error) {
+	if e.uuid {
+		return workflow.ReserveUUID()
+	}
+
+	return workflow.ReserveID(workflow.WORKFLOW_INDEX_KEY, e.cache)
+}
+
+func (e *Sagawf) GetWorkflow(id string) (workflow.Workflow, error) {
+	return workflow.LoadWorkflow(e.cache, id)
+}
+
+func (e *Sagawf) RegisterHandler(id string) chan workflow.WorkflowPayload {
+	return e.handler.register(id)
+}
+
+func (e *Sagawf) UnregisterHandler(id string, ch chan workflow.WorkflowPayload) {
+	e.handler.unregister(id, ch)
+}
+
+func (e *Sagawf) SetWorkflow(id string, w workflow.Workflow) error {
+	return workflow.SaveWorkflow(e.cache, id, w)
+}
+
+// Recover resumes the active workflows of the cache when the coordinator is started
+func (e *Sagawf) Recover(grace time.Duration) error {
+	ids, err := workflow.GetActiveIDs(e.cache)
 	if err != nil {
-		return 0, err
+		return err
+	}
+
+	now := time.Now()
+	for _, id := range ids {
+		w, err := e.GetWorkflow(id)
+		if err == nil {
+			proc := e.CreateProcessor()
+			err = proc.Recover(w, id, grace, now)
+		}
+
+		// a broken workflow should not block the start of the coordinator
+		if err != nil {
+			fmt.Printf("%s workflow recovery is failed: %v\n", id, err)
+			continue
+		}
+
+		fmt.Printf("%s %s workflow is recovered\n", w.Name, id)
 	}
 
-	return id, nil
+	return nil
 }
 
-func (e *Sagawf) GetWorkflow(id int) (workflow.Workflow, error) {
-	ctx := context.Background()
-	key := getWorkflowKey(id)
+func (e *Sagawf) CheckTimeouts(now time.Time) error {
+	err := workflow.RemoveExpiredIdempotencyRecords(e.cache, now)
+	if err != nil {
+		fmt.Printf("expired idempotency keys removal is failed: %v\n", err)
+	}
 
-	var w workflow.Workflow
-	raw, err := e.cache.Get(ctx, key)
+	err = workflow.RemoveExpiredLeases(e.cache, now)
 	if err != nil {
+		fmt.Printf("expired coordinator leases removal is failed: %v\n", err)
+	}
+
+	ids, err := workflow.GetActiveIDs(e.cache)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		// the messages left in the outbox by a broker failure are published when the broker is back
+		proc := e.CreateProcessor()
+		err := proc.Relay(id, now)
+		if err != nil {
+			fmt.Printf("%s workflow outbox relay is failed: %v\n", id, err)
+		}
+
+		w, err := e.GetWorkflow(id)
+		if err == nil {
+			err = proc.OnTimeout(w, id, now)
+		}
+
+		// a broken workflow should not block timeouts of the others
+		if err != nil {
+			fmt.Printf("%s workflow timeouts check is failed: %v\n", id, err)
+		}
+	}
+
+	return nil
+}
+
+func (e *Sagawf) runTimers(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		err := workflow.RenewLease(e.cache, e.owner, now.Add(e.lease))
+		if err != nil {
+			fmt.Printf("coordinator lease renewal is failed: %v\n", err)
+		}
+
+		err = e.CheckTimeouts(now)
+		if err != nil {
+			fmt.Printf("timeouts check is failed: %v\n", err)
+		}
+	}
+}
+
+func (e *Sagawf) toWorkflow(req *pb.WorkflowRequest) (workflow.Workflow, error) {
+	if req.DefinitionName == "" {
+		w := workflow.ToWorkflow(req)
+		err := workflow.Validate(w)
+		if err == nil {
+			err = e.executors.Validate(w)
+		}
+		return w, toValidationError(err)
+	}
+
+	// the run keeps its own copy of the definition so it is pinned to the version
+	w, err := workflow.GetDefinition(e.cache, req.DefinitionName, int(req.DefinitionVersion))
+	if err == workflow.ErrDefinitionNotFound {
+		return w, errors.NotFound("sagawf.definition.not_found", "workflow definition %s version %d is not found", req.DefinitionName, req.DefinitionVersion)
+	} else if err != nil {
 		return w, err
 	}
 
-	json.Unmarshal([]byte(raw), &w)
-	return w, nil
+	// the definition registered by a replica with other executors is rejected before it is started
+	w.Payload = req.Payload
+	return w, toValidationError(e.executors.Validate(w))
 }
 
-func (e *Sagawf) RegisterHandler(id int) chan workflow.WorkflowPayload {
-	result := make(chan workflow.WorkflowPayload)
-	e.handler[id] = result
+func toValidationError(err error) error {
+	if verr, ok := err.(*workflow.ValidationError); ok {
+		detail, err := json.Marshal(verr)
+		if err != nil {
+			return err
+		}
+
+		return errors.BadRequest("sagawf.workflow.invalid", "%s", detail)
+	}
 
-	return result
+	return err
 }
 
-func (e *Sagawf) SetWorkflow(id int, w workflow.Workflow) error {
-	ctx := context.Background()
-	key := getWorkflowKey(id)
-	return e.cache.Set(ctx, key, w)
+func (e *Sagawf) startWorkflow(id string, w workflow.Workflow) error {
+	proc := e.CreateProcessor()
+
+	err := e.SetWorkflow(id, w)
+	if err != nil {
+		return err
+	}
+
+	return proc.StartWorkflow(w, id)
+}
+
+// reserveRequestID returns the id of the workflow already started by the request with the same idempotency key
+func (e *Sagawf) reserveRequestID(req *pb.WorkflowRequest) (string, bool, error) {
+	if req.IdempotencyKey == "" {
+		id, err := e.ReserveID()
+		return id, false, err
+	}
+
+	return workflow.ReserveIdempotentID(e.cache, req.IdempotencyKey, e.retention, time.Now(), e.ReserveID)
+}
+
+// startRequest starts the workflow of the request and confirms its idempotency key,
+// the key is released if the start is failed so the retried request starts the workflow again
+func (e *Sagawf) startRequest(req *pb.WorkflowRequest, id string, w workflow.Workflow) error {
+	err := e.startWorkflow(id, w)
+	if req.IdempotencyKey == "" {
+		return err
+	}
+
+	if err != nil {
+		releaseErr := workflow.ReleaseIdempotentID(e.cache, req.IdempotencyKey, id)
+		if releaseErr != nil {
+			fmt.Printf("%s idempotency key release is failed: %v\n", req.IdempotencyKey, releaseErr)
+		}
+		return err
+	}
+
+	return workflow.ConfirmIdempotentID(e.cache, req.IdempotencyKey, id)
+}
+
+// getResult returns the result of the ended or stuck workflow
+func (e *Sagawf) getResult(id string, name string) (workflow.WorkflowPayload, bool, error) {
+	status, err := workflow.GetWorkflowStatus(e.cache, id)
+	if err == mc.ErrKeyNotFound {
+		// the workflow of the concurrent request is not started yet
+		return workflow.WorkflowPayload{}, false, nil
+	} else if err != nil {
+		return workflow.WorkflowPayload{}, false, err
+	}
+
+	result := workflow.WorkflowPayload{
+		ID:         id,
+		IsRollback: status.IsRollback,
+		IsStuck:    status.IsStuck,
+		Name:       name,
+		Data:       status.Data,
+	}
+
+	return result, status.Completed || status.IsStuck, nil
 }
 
 func (e *Sagawf) RunWorkflow(ctx context.Context, req *pb.WorkflowRequest, rsp *pb.WorkflowResponse) error {
-	proc := e.CreateProcessor()
-	id, err := e.ReserveID()
+	w, err := e.toWorkflow(req)
+	if err != nil {
+		return err
+	}
+
+	id, found, err := e.reserveRequestID(req)
 
 	if err != nil {
 		return err
 	}
 
+	// the handler is registered before the state is checked so the result of the running workflow is not missed
 	targetCh := e.RegisterHandler(id)
+	defer e.UnregisterHandler(id, targetCh)
+
+	var response workflow.WorkflowPayload
+	isEnded := false
+	if found {
+		response, isEnded, err = e.getResult(id, w.Name)
+	} else {
+		err = e.startRequest(req, id, w)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !isEnded {
+		select {
+		case response = <-targetCh:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	rsp.WorkflowRef = &pb.WorkflowRef{
+		Name:       w.Name,
+		IsRollback: response.IsRollback,
+		IsStuck:    response.IsStuck,
+	}
+	setRefID(rsp.WorkflowRef, id)
+
+	rsp.State, err = toState(response.Data)
+	return err
+}
+
+func (e *Sagawf) StartWorkflow(ctx context.Context, req *pb.WorkflowRequest, rsp *pb.WorkflowRef) error {
+	w, err := e.toWorkflow(req)
+	if err != nil {
+		return err
+	}
+
+	id, found, err := e.reserveRequestID(req)
 
-	w := workflow.ToWorkflow(req)
-	err = e.SetWorkflow(id, w)
 	if err != nil {
 		return err
 	}
 
-	proc.StartWorkflow(w, id)
+	if !found {
+		err = e.startRequest(req, id, w)
+		if err != nil {
+			return err
+		}
+	}
+
+	setRefID(rsp, id)
+	rsp.Name = w.Name
+	return nil
+}
+
+func (e *Sagawf) GetWorkflowStatus(ctx context.Context, req *pb.WorkflowRef, rsp *pb.WorkflowStatus) error {
+	id := getRefID(req)
+
+	w, err := e.GetWorkflow(id)
 	if err != nil {
 		return err
 	}
 
-	response := <-targetCh
+	status, err := workflow.GetWorkflowStatus(e.cache, id)
+	if err != nil {
+		return err
+	}
 
 	rsp.WorkflowRef = &pb.WorkflowRef{
-		Id:         int64(id),
-		Name:       req.Name,
-		IsRollback: response.IsRollback,
+		Name:       w.Name,
+		IsRollback: status.IsRollback,
+		IsStuck:    status.IsStuck,
+	}
+	setRefID(rsp.WorkflowRef, id)
+	rsp.Completed = status.Completed
+	rsp.Done = toOperationStatuses(status.Done)
+	rsp.InProgress = toOperationStatuses(status.InProgress)
+	rsp.Failed = toOperationStatuses(status.Failed)
+	rsp.Skipped = toOperationStatuses(status.Skipped)
+	rsp.Settled = toOperationStatuses(status.Settled)
+	rsp.ParentId = status.ParentID
+	rsp.Children = status.Children
+	rsp.IsPaused = status.IsPaused
+	rsp.IsCancelled = status.IsCancelled
+
+	rsp.State, err = toState(status.Data)
+	return err
+}
+
+func (e *Sagawf) WatchWorkflow(ctx context.Context, req *pb.WorkflowRef, stream pb.Sagawf_WatchWorkflowStream) error {
+	id := getRefID(req)
+
+	watcher := e.watchers.add(id)
+	defer e.watchers.remove(id, watcher)
+
+	w, err := e.GetWorkflow(id)
+	if err != nil {
+		return err
+	}
+
+	status, err := workflow.GetWorkflowStatus(e.cache, id)
+	if err != nil {
+		return err
 	}
 
-	rsp.State = make(map[string]*pb.State)
+	// the workflow has been finished before the watcher was registered
+	if status.Completed || status.IsStuck {
+		event, err := newWorkflowEvent(workflow.WorkflowPayload{
+			ID:         id,
+			IsRollback: status.IsRollback,
+			IsStuck:    status.IsStuck,
+			Name:       w.Name,
+			Data:       status.Data,
+		})
+		if err != nil {
+			return err
+		}
+
+		return stream.Send(event)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-watcher.signal:
+			for _, event := range watcher.pop() {
+				err := stream.Send(event)
+				if err != nil {
+					return err
+				}
+
+				if isTerminal(event) {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+func (e *Sagawf) RedriveWorkflow(ctx context.Context, req *pb.WorkflowRef, rsp *pb.WorkflowRef) error {
+	id := getRefID(req)
+
+	w, err := e.GetWorkflow(id)
+	if err != nil {
+		return err
+	}
+
+	proc := e.CreateProcessor()
+	err = proc.Redrive(w, id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s workflow is redriven\n", w.Name, id)
+
+	setRefID(rsp, id)
+	rsp.Name = w.Name
+	rsp.IsRollback = true
+	return nil
+}
+
+func (e *Sagawf) CancelWorkflow(ctx context.Context, req *pb.WorkflowRef, rsp *pb.WorkflowRef) error {
+	id := getRefID(req)
+
+	w, err := e.GetWorkflow(id)
+	if err != nil {
+		return err
+	}
+
+	proc := e.CreateProcessor()
+	err = proc.Cancel(w, id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s workflow is cancelled\n", w.Name, id)
+
+	setRefID(rsp, id)
+	rsp.Name = w.Name
+	rsp.IsRollback = true
+	return nil
+}
+
+func (e *Sagawf) PauseWorkflow(ctx context.Context, req *pb.WorkflowRef, rsp *pb.WorkflowRef) error {
+	id := getRefID(req)
+
+	w, err := e.GetWorkflow(id)
+	if err != nil {
+		return err
+	}
+
+	proc := e.CreateProcessor()
+	err = proc.Pause(w, id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s workflow is paused\n", w.Name, id)
+
+	return e.getWorkflowRef(id, w.Name, rsp)
+}
+
+func (e *Sagawf) ResumeWorkflow(ctx context.Context, req *pb.WorkflowRef, rsp *pb.WorkflowRef) error {
+	id := getRefID(req)
+
+	w, err := e.GetWorkflow(id)
+	if err != nil {
+		return err
+	}
+
+	proc := e.CreateProcessor()
+	err = proc.Continue(w, id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s workflow is resumed\n", w.Name, id)
+
+	return e.getWorkflowRef(id, w.Name, rsp)
+}
 
-	for s, v := range response.Data {
+func (e *Sagawf) ForceCompleteOperation(ctx context.Context, req *pb.OperationRequest, rsp *pb.WorkflowRef) error {
+	id := req.Id
+
+	w, err := e.GetWorkflow(id)
+	if err != nil {
+		return err
+	}
+
+	proc := e.CreateProcessor()
+	err = proc.ForceComplete(w, id, req.Operation, req.IsRollback, req.Payload)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s operation of %s %s workflow is completed manually\n", req.Operation, w.Name, id)
+
+	return e.getWorkflowRef(id, w.Name, rsp)
+}
+
+func (e *Sagawf) SkipOperation(ctx context.Context, req *pb.OperationRequest, rsp *pb.WorkflowRef) error {
+	id := req.Id
+
+	w, err := e.GetWorkflow(id)
+	if err != nil {
+		return err
+	}
+
+	proc := e.CreateProcessor()
+	err = proc.Skip(w, id, req.Operation, req.IsRollback)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s operation of %s %s workflow is skipped manually\n", req.Operation, w.Name, id)
+
+	return e.getWorkflowRef(id, w.Name, rsp)
+}
+
+// getWorkflowRef fills the reference with the current status of the workflow
+func (e *Sagawf) getWorkflowRef(id string, name string, rsp *pb.WorkflowRef) error {
+	status, err := workflow.GetWorkflowStatus(e.cache, id)
+	if err != nil {
+		return err
+	}
+
+	setRefID(rsp, id)
+	rsp.Name = name
+	rsp.IsRollback = status.IsRollback
+	rsp.IsStuck = status.IsStuck
+	return nil
+}
+
+func (e *Sagawf) RegisterWorkflowDefinition(ctx context.Context, req *pb.WorkflowDefinition, rsp *pb.WorkflowDefinition) error {
+	w := workflow.FromDefinition(req)
+	err := e.executors.Validate(w)
+	if err != nil {
+		return toValidationError(err)
+	}
+
+	w, err = workflow.RegisterDefinition(e.cache, w)
+	if err != nil {
+		return toValidationError(err)
+	}
+
+	fmt.Printf("%s workflow definition version %d is registered\n", w.Name, w.Version)
+
+	toDefinition(w, rsp)
+	return nil
+}
+
+func (e *Sagawf) ListWorkflowDefinitions(ctx context.Context, req *pb.ListWorkflowDefinitionsRequest, rsp *pb.ListWorkflowDefinitionsResponse) error {
+	definitions, err := workflow.ListDefinitions(e.cache)
+	if err != nil {
+		return err
+	}
+
+	rsp.Definitions = []*pb.WorkflowDefinition{}
+	for _, w := range definitions {
+		def := pb.WorkflowDefinition{}
+		toDefinition(w, &def)
+		rsp.Definitions = append(rsp.Definitions, &def)
+	}
+
+	return nil
+}
+
+func (e *Sagawf) GetWorkflowDefinition(ctx context.Context, req *pb.WorkflowDefinitionRef, rsp *pb.WorkflowDefinition) error {
+	w, err := workflow.GetDefinition(e.cache, req.Name, int(req.Version))
+	if err == workflow.ErrDefinitionNotFound {
+		return errors.NotFound("sagawf.definition.not_found", "workflow definition %s version %d is not found", req.Name, req.Version)
+	} else if err != nil {
+		return err
+	}
+
+	toDefinition(w, rsp)
+	return nil
+}
+
+// ExportWorkflow renders the running workflow with its state or the registered definition
+func (e *Sagawf) ExportWorkflow(ctx context.Context, req *pb.ExportRequest, rsp *pb.ExportResponse) error {
+	format := req.Format
+	switch format {
+	case "":
+		format = workflow.FORMAT_DOT
+	case workflow.FORMAT_DOT, workflow.FORMAT_MERMAID:
+	default:
+		return errors.BadRequest("sagawf.export.invalid", "unknown export format: %s", format)
+	}
+
+	var graph string
+	if req.Id != "" {
+		result, err := workflow.ExportWorkflow(e.cache, req.Id, format)
+		if err != nil {
+			return err
+		}
+
+		graph = result
+	} else {
+		w, err := workflow.GetDefinition(e.cache, req.DefinitionName, int(req.DefinitionVersion))
+		if err == workflow.ErrDefinitionNotFound {
+			return errors.NotFound("sagawf.definition.not_found", "workflow definition %s version %d is not found", req.DefinitionName, req.DefinitionVersion)
+		} else if err != nil {
+			return err
+		}
+
+		graph, err = workflow.Export(w, format, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	rsp.Graph = graph
+	return nil
+}
+
+func toDefinition(w workflow.Workflow, def *pb.WorkflowDefinition) {
+	def.Name = w.Name
+	def.Version = int32(w.Version)
+	def.Start = w.Start
+	def.End = w.End
+	def.TimeoutMs = w.Timeout.Milliseconds()
+	def.Metadata = w.Metadata
+	def.Operations = []*pb.Operation{}
+
+	for _, op := range w.Operations {
+		def.Operations = append(def.Operations, &pb.Operation{
+			Name:              op.Name,
+			From:              op.From,
+			To:                op.To,
+			Retry:             toRetryPolicy(op.Retry),
+			TimeoutMs:         op.Timeout.Milliseconds(),
+			CompensationRetry: toRetryPolicy(op.CompensationRetry),
+			Condition:         op.Condition,
+			ForEach:           op.ForEach,
+			Workflow:          op.Workflow,
+			WorkflowVersion:   int32(op.WorkflowVersion),
+			Type:              op.Type,
+			Service:           op.Service,
+		})
+	}
+}
+
+func toRetryPolicy(policy *workflow.RetryPolicy) *pb.RetryPolicy {
+	if policy == nil {
+		return nil
+	}
+
+	return &pb.RetryPolicy{
+		MaxAttempts:      int32(policy.MaxAttempts),
+		InitialBackoffMs: policy.InitialBackoff.Milliseconds(),
+		Multiplier:       policy.Multiplier,
+		MaxBackoffMs:     policy.MaxBackoff.Milliseconds(),
+	}
+}
+
+func toOperationStatuses(ops []workflow.OperationStatus) []*pb.OperationStatus {
+	result := []*pb.OperationStatus{}
+	for _, op := range ops {
+		result = append(result, &pb.OperationStatus{
+			Operation: &pb.Operation{
+				Name: op.Operation.Name,
+				From: op.Operation.From,
+				To:   op.Operation.To,
+			},
+			IsRollback: op.IsRollback,
+		})
+	}
+
+	return result
+}
+
+// getRefID returns the workflow id of the reference, the numeric id is used for the clients which do not set the workflow id
+func getRefID(ref *pb.WorkflowRef) string {
+	if ref.WorkflowId != "" || ref.Id == 0 {
+		return ref.WorkflowId
+	}
+
+	return strconv.FormatInt(ref.Id, 10)
+}
+
+// setRefID sets the workflow id of the reference and the numeric id if the workflow id is numeric
+func setRefID(ref *pb.WorkflowRef, id string) {
+	ref.WorkflowId = id
+	if legacy, err := strconv.ParseInt(id, 10, 64); err == nil {
+		ref.Id = legacy
+	}
+}
+
+func toState(data map[string]map[string]interface{}) (map[string]*pb.State, error) {
+	result := make(map[string]*pb.State)
+
+	for s, v := range data {
 		st := pb.State{
 			State: make(map[string]string),
 		}
-		rsp.State[s] = &st
+		result[s] = &st
 		for op, state := range v {
 			opValue, err := json.Marshal(state)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			st.State[op] = string(opValue)
 		}
 	}
-	return nil
+
+	return result, nil
 }
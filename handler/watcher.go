@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/awe76/sagawf/workflow"
+
+	pb "github.com/awe76/sagawf/proto"
+)
+
+type watcher struct {
+	mu     sync.Mutex
+	events []*pb.WorkflowEvent
+	signal chan struct{}
+}
+
+func (w *watcher) push(e *pb.WorkflowEvent) {
+	w.mu.Lock()
+	w.events = append(w.events, e)
+	w.mu.Unlock()
+
+	select {
+	case w.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (w *watcher) pop() []*pb.WorkflowEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	events := w.events
+	w.events = nil
+	return events
+}
+
+type watchers struct {
+	mu       sync.RWMutex
+	watchers map[string]map[*watcher]bool
+}
+
+func newWatchers() *watchers {
+	return &watchers{
+		watchers: make(map[string]map[*watcher]bool),
+	}
+}
+
+func (ws *watchers) add(id string) *watcher {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	w := &watcher{
+		signal: make(chan struct{}, 1),
+	}
+
+	if _, found := ws.watchers[id]; !found {
+		ws.watchers[id] = make(map[*watcher]bool)
+	}
+	ws.watchers[id][w] = true
+
+	return w
+}
+
+func (ws *watchers) remove(id string, w *watcher) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	delete(ws.watchers[id], w)
+	if len(ws.watchers[id]) == 0 {
+		delete(ws.watchers, id)
+	}
+}
+
+func (ws *watchers) notify(id string, e *pb.WorkflowEvent) {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+
+	for w := range ws.watchers[id] {
+		w.push(e)
+	}
+}
+
+func isTerminal(e *pb.WorkflowEvent) bool {
+	switch e.Type {
+	case pb.EventType_WORKFLOW_COMPLETED, pb.EventType_WORKFLOW_ROLLBACKED, pb.EventType_WORKFLOW_STUCK:
+		return true
+	default:
+		return false
+	}
+}
+
+func newOperationEvent(op workflow.OperationPayload, forward pb.EventType, rollback pb.EventType) (*pb.WorkflowEvent, error) {
+	eventType := forward
+	if op.IsRollback {
+		eventType = rollback
+	}
+
+	payload, err := json.Marshal(op.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := &pb.WorkflowRef{
+		Name:       op.Name,
+		IsRollback: op.IsRollback,
+	}
+	setRefID(ref, op.ID)
+
+	return &pb.WorkflowEvent{
+		WorkflowRef: ref,
+		Type:        eventType,
+		Operation: &pb.Operation{
+			Name: op.Operation.Name,
+			From: op.Operation.From,
+			To:   op.Operation.To,
+		},
+		Payload: string(payload),
+	}, nil
+}
+
+func newWorkflowEvent(w workflow.WorkflowPayload) (*pb.WorkflowEvent, error) {
+	eventType := pb.EventType_WORKFLOW_COMPLETED
+	if w.IsStuck {
+		eventType = pb.EventType_WORKFLOW_STUCK
+	} else if w.IsRollback {
+		eventType = pb.EventType_WORKFLOW_ROLLBACKED
+	}
+
+	state, err := toState(w.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := &pb.WorkflowRef{
+		Name:       w.Name,
+		IsRollback: w.IsRollback,
+		IsStuck:    w.IsStuck,
+	}
+	setRefID(ref, w.ID)
+
+	return &pb.WorkflowEvent{
+		WorkflowRef: ref,
+		Type:        eventType,
+		State:       state,
+	}, nil
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/awe76/sagawf/workflow"
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/awe76/sagawf/proto"
+)
+
+type streamMock struct {
+	mu     sync.Mutex
+	ctx    context.Context
+	events []*pb.WorkflowEvent
+}
+
+func (s *streamMock) Context() context.Context {
+	return s.ctx
+}
+
+func (s *streamMock) SendMsg(m interface{}) error {
+	return s.Send(m.(*pb.WorkflowEvent))
+}
+
+func (s *streamMock) RecvMsg(m interface{}) error {
+	return nil
+}
+
+func (s *streamMock) Close() error {
+	return nil
+}
+
+func (s *streamMock) Send(e *pb.WorkflowEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, e)
+	return nil
+}
+
+func (s *streamMock) get() []*pb.WorkflowEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*pb.WorkflowEvent{}, s.events...)
+}
+
+func startWatched(t *testing.T) *Sagawf {
+	cache := workflow.NewCacheMock()
+	w := workflow.Workflow{
+		Name:       "order",
+		Start:      "s1",
+		End:        "s2",
+		Operations: []workflow.Operation{{Name: "op1", From: "s1", To: "s2"}},
+	}
+
+	assert.NoError(t, workflow.SaveWorkflow(cache, "1", w))
+	assert.NoError(t, workflow.NewProcessor(cache, workflow.NewProducerMock()).StartWorkflow(w, "1"))
+
+	return &Sagawf{
+		cache:    cache,
+		watchers: newWatchers(),
+	}
+}
+
+func (ws *watchers) count(id string) int {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+
+	return len(ws.watchers[id])
+}
+
+func TestWatchWorkflowCancel(t *testing.T) {
+	e := startWatched(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &streamMock{ctx: ctx}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- e.WatchWorkflow(ctx, &pb.WorkflowRef{WorkflowId: "1"}, stream)
+	}()
+
+	assert.Eventually(t, func() bool { return e.watchers.count("1") == 1 }, time.Second, time.Millisecond)
+
+	e.watchers.notify("1", &pb.WorkflowEvent{Type: pb.EventType_OPERATION_COMPLETED})
+	assert.Eventually(t, func() bool { return len(stream.get()) == 1 }, time.Second, time.Millisecond)
+
+	// the cancelled caller stops the watcher and removes it
+	cancel()
+	select {
+	case err := <-done:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(time.Second):
+		t.Fatal("watcher is not stopped")
+	}
+	assert.Equal(t, 0, e.watchers.count("1"))
+
+	// the events of the removed watcher are not delivered
+	e.watchers.notify("1", &pb.WorkflowEvent{Type: pb.EventType_OPERATION_COMPLETED})
+	assert.Len(t, stream.get(), 1)
+}
+
+func TestWatchWorkflowTerminal(t *testing.T) {
+	e := startWatched(t)
+	stream := &streamMock{ctx: context.Background()}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- e.WatchWorkflow(context.Background(), &pb.WorkflowRef{WorkflowId: "1"}, stream)
+	}()
+
+	assert.Eventually(t, func() bool { return e.watchers.count("1") == 1 }, time.Second, time.Millisecond)
+
+	// the terminal event ends the stream
+	e.watchers.notify("1", &pb.WorkflowEvent{Type: pb.EventType_WORKFLOW_COMPLETED})
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("watcher is not stopped")
+	}
+	assert.Equal(t, 0, e.watchers.count("1"))
+	assert.Len(t, stream.get(), 1)
+}
@@ -1,8 +1,14 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"time"
+
 	"github.com/awe76/sagawf/handler"
 	pb "github.com/awe76/sagawf/proto"
+	"github.com/awe76/sagawf/workflow"
+	"github.com/urfave/cli/v2"
 
 	"go-micro.dev/v4"
 	log "go-micro.dev/v4/logger"
@@ -13,15 +19,112 @@ var (
 	version = "latest"
 )
 
+func newCache(name string, path string) (workflow.Cache, error) {
+	switch name {
+	case "memory":
+		return workflow.NewCache(), nil
+	case "file":
+		return workflow.NewFileCache(path)
+	default:
+		return nil, fmt.Errorf("unknown workflow cache: %s", name)
+	}
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	var opts []handler.Option
+
 	// Create service
 	srv := micro.NewService(
 		micro.Name(service),
 		micro.Version(version),
+		micro.Flags(
+			&cli.StringFlag{
+				Name:    "workflow_cache",
+				Usage:   "Workflow cache backend: memory or file",
+				EnvVars: []string{"SAGAWF_CACHE"},
+				Value:   "memory",
+			},
+			&cli.StringFlag{
+				Name:    "workflow_cache_path",
+				Usage:   "Path to the file cache database",
+				EnvVars: []string{"SAGAWF_CACHE_PATH"},
+				Value:   "sagawf.db",
+			},
+			&cli.StringFlag{
+				Name:    "workflow_id",
+				Usage:   "Workflow id allocation: counter or uuid",
+				EnvVars: []string{"SAGAWF_ID"},
+				Value:   "counter",
+			},
+			&cli.StringFlag{
+				Name:    "workflow_definitions",
+				Usage:   "Directory of workflow definition files registered at startup",
+				EnvVars: []string{"SAGAWF_DEFINITIONS"},
+			},
+			&cli.StringFlag{
+				Name:    "workflow_namespace",
+				Usage:   "Namespace prefixed to the workflow topics",
+				EnvVars: []string{"SAGAWF_NAMESPACE"},
+			},
+			&cli.StringFlag{
+				Name:    "workflow_queue",
+				Usage:   "Queue group shared by the coordinator replicas",
+				EnvVars: []string{"SAGAWF_QUEUE"},
+				Value:   "sagawf",
+			},
+			&cli.DurationFlag{
+				Name:    "workflow_idempotency_retention",
+				Usage:   "Period the idempotency key of the request is mapped to its workflow",
+				EnvVars: []string{"SAGAWF_IDEMPOTENCY_RETENTION"},
+				Value:   24 * time.Hour,
+			},
+			&cli.DurationFlag{
+				Name:    "workflow_recovery_grace",
+				Usage:   "Operations in progress longer than it are started again on restart",
+				EnvVars: []string{"SAGAWF_RECOVERY_GRACE"},
+				Value:   30 * time.Second,
+			},
+		),
+		micro.Action(func(c *cli.Context) error {
+			cache, err := newCache(c.String("workflow_cache"), c.String("workflow_cache_path"))
+			if err != nil {
+				return err
+			}
+
+			opts = append(opts, handler.Cache(cache))
+
+			switch c.String("workflow_id") {
+			case "counter":
+			case "uuid":
+				opts = append(opts, handler.UUID())
+			default:
+				return fmt.Errorf("unknown workflow id allocation: %s", c.String("workflow_id"))
+			}
+
+			if dir := c.String("workflow_definitions"); dir != "" {
+				opts = append(opts, handler.Definitions(dir))
+			}
+
+			opts = append(opts, handler.Namespace(c.String("workflow_namespace")), handler.Queue(c.String("workflow_queue")))
+			opts = append(opts, handler.IdempotencyRetention(c.Duration("workflow_idempotency_retention")))
+			opts = append(opts, handler.RecoveryGrace(c.Duration("workflow_recovery_grace")))
+
+			return nil
+		}),
 	)
 	srv.Init()
 
-	handler, err := handler.NewSagawf(srv.Client())
+	// the broker selected by the service flags carries the workflow events
+	opts = append(opts, handler.Broker(srv.Options().Broker))
+
+	handler, err := handler.NewSagawf(srv.Client(), opts...)
 
 	if err != nil {
 		log.Fatal(err)
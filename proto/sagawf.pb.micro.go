@@ -37,6 +37,19 @@ func NewSagawfEndpoints() []*api.Endpoint {
 
 type SagawfService interface {
 	RunWorkflow(ctx context.Context, in *WorkflowRequest, opts ...client.CallOption) (*WorkflowResponse, error)
+	StartWorkflow(ctx context.Context, in *WorkflowRequest, opts ...client.CallOption) (*WorkflowRef, error)
+	GetWorkflowStatus(ctx context.Context, in *WorkflowRef, opts ...client.CallOption) (*WorkflowStatus, error)
+	WatchWorkflow(ctx context.Context, in *WorkflowRef, opts ...client.CallOption) (Sagawf_WatchWorkflowService, error)
+	RedriveWorkflow(ctx context.Context, in *WorkflowRef, opts ...client.CallOption) (*WorkflowRef, error)
+	CancelWorkflow(ctx context.Context, in *WorkflowRef, opts ...client.CallOption) (*WorkflowRef, error)
+	PauseWorkflow(ctx context.Context, in *WorkflowRef, opts ...client.CallOption) (*WorkflowRef, error)
+	ResumeWorkflow(ctx context.Context, in *WorkflowRef, opts ...client.CallOption) (*WorkflowRef, error)
+	ForceCompleteOperation(ctx context.Context, in *OperationRequest, opts ...client.CallOption) (*WorkflowRef, error)
+	SkipOperation(ctx context.Context, in *OperationRequest, opts ...client.CallOption) (*WorkflowRef, error)
+	RegisterWorkflowDefinition(ctx context.Context, in *WorkflowDefinition, opts ...client.CallOption) (*WorkflowDefinition, error)
+	ListWorkflowDefinitions(ctx context.Context, in *ListWorkflowDefinitionsRequest, opts ...client.CallOption) (*ListWorkflowDefinitionsResponse, error)
+	GetWorkflowDefinition(ctx context.Context, in *WorkflowDefinitionRef, opts ...client.CallOption) (*WorkflowDefinition, error)
+	ExportWorkflow(ctx context.Context, in *ExportRequest, opts ...client.CallOption) (*ExportResponse, error)
 }
 
 type sagawfService struct {
@@ -61,15 +74,215 @@ func (c *sagawfService) RunWorkflow(ctx context.Context, in *WorkflowRequest, op
 	return out, nil
 }
 
+func (c *sagawfService) StartWorkflow(ctx context.Context, in *WorkflowRequest, opts ...client.CallOption) (*WorkflowRef, error) {
+	req := c.c.NewRequest(c.name, "Sagawf.StartWorkflow", in)
+	out := new(WorkflowRef)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagawfService) GetWorkflowStatus(ctx context.Context, in *WorkflowRef, opts ...client.CallOption) (*WorkflowStatus, error) {
+	req := c.c.NewRequest(c.name, "Sagawf.GetWorkflowStatus", in)
+	out := new(WorkflowStatus)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagawfService) WatchWorkflow(ctx context.Context, in *WorkflowRef, opts ...client.CallOption) (Sagawf_WatchWorkflowService, error) {
+	req := c.c.NewRequest(c.name, "Sagawf.WatchWorkflow", &WorkflowRef{})
+	stream, err := c.c.Stream(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(in); err != nil {
+		return nil, err
+	}
+	return &sagawfServiceWatchWorkflow{stream}, nil
+}
+
+type Sagawf_WatchWorkflowService interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	CloseSend() error
+	Close() error
+	Recv() (*WorkflowEvent, error)
+}
+
+type sagawfServiceWatchWorkflow struct {
+	stream client.Stream
+}
+
+func (x *sagawfServiceWatchWorkflow) CloseSend() error {
+	return x.stream.CloseSend()
+}
+
+func (x *sagawfServiceWatchWorkflow) Close() error {
+	return x.stream.Close()
+}
+
+func (x *sagawfServiceWatchWorkflow) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *sagawfServiceWatchWorkflow) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *sagawfServiceWatchWorkflow) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *sagawfServiceWatchWorkflow) Recv() (*WorkflowEvent, error) {
+	m := new(WorkflowEvent)
+	err := x.stream.Recv(m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *sagawfService) RedriveWorkflow(ctx context.Context, in *WorkflowRef, opts ...client.CallOption) (*WorkflowRef, error) {
+	req := c.c.NewRequest(c.name, "Sagawf.RedriveWorkflow", in)
+	out := new(WorkflowRef)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagawfService) CancelWorkflow(ctx context.Context, in *WorkflowRef, opts ...client.CallOption) (*WorkflowRef, error) {
+	req := c.c.NewRequest(c.name, "Sagawf.CancelWorkflow", in)
+	out := new(WorkflowRef)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagawfService) PauseWorkflow(ctx context.Context, in *WorkflowRef, opts ...client.CallOption) (*WorkflowRef, error) {
+	req := c.c.NewRequest(c.name, "Sagawf.PauseWorkflow", in)
+	out := new(WorkflowRef)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagawfService) ResumeWorkflow(ctx context.Context, in *WorkflowRef, opts ...client.CallOption) (*WorkflowRef, error) {
+	req := c.c.NewRequest(c.name, "Sagawf.ResumeWorkflow", in)
+	out := new(WorkflowRef)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagawfService) ForceCompleteOperation(ctx context.Context, in *OperationRequest, opts ...client.CallOption) (*WorkflowRef, error) {
+	req := c.c.NewRequest(c.name, "Sagawf.ForceCompleteOperation", in)
+	out := new(WorkflowRef)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagawfService) SkipOperation(ctx context.Context, in *OperationRequest, opts ...client.CallOption) (*WorkflowRef, error) {
+	req := c.c.NewRequest(c.name, "Sagawf.SkipOperation", in)
+	out := new(WorkflowRef)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagawfService) RegisterWorkflowDefinition(ctx context.Context, in *WorkflowDefinition, opts ...client.CallOption) (*WorkflowDefinition, error) {
+	req := c.c.NewRequest(c.name, "Sagawf.RegisterWorkflowDefinition", in)
+	out := new(WorkflowDefinition)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagawfService) ListWorkflowDefinitions(ctx context.Context, in *ListWorkflowDefinitionsRequest, opts ...client.CallOption) (*ListWorkflowDefinitionsResponse, error) {
+	req := c.c.NewRequest(c.name, "Sagawf.ListWorkflowDefinitions", in)
+	out := new(ListWorkflowDefinitionsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagawfService) GetWorkflowDefinition(ctx context.Context, in *WorkflowDefinitionRef, opts ...client.CallOption) (*WorkflowDefinition, error) {
+	req := c.c.NewRequest(c.name, "Sagawf.GetWorkflowDefinition", in)
+	out := new(WorkflowDefinition)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagawfService) ExportWorkflow(ctx context.Context, in *ExportRequest, opts ...client.CallOption) (*ExportResponse, error) {
+	req := c.c.NewRequest(c.name, "Sagawf.ExportWorkflow", in)
+	out := new(ExportResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Sagawf service
 
 type SagawfHandler interface {
 	RunWorkflow(context.Context, *WorkflowRequest, *WorkflowResponse) error
+	StartWorkflow(context.Context, *WorkflowRequest, *WorkflowRef) error
+	GetWorkflowStatus(context.Context, *WorkflowRef, *WorkflowStatus) error
+	WatchWorkflow(context.Context, *WorkflowRef, Sagawf_WatchWorkflowStream) error
+	RedriveWorkflow(context.Context, *WorkflowRef, *WorkflowRef) error
+	CancelWorkflow(context.Context, *WorkflowRef, *WorkflowRef) error
+	PauseWorkflow(context.Context, *WorkflowRef, *WorkflowRef) error
+	ResumeWorkflow(context.Context, *WorkflowRef, *WorkflowRef) error
+	ForceCompleteOperation(context.Context, *OperationRequest, *WorkflowRef) error
+	SkipOperation(context.Context, *OperationRequest, *WorkflowRef) error
+	RegisterWorkflowDefinition(context.Context, *WorkflowDefinition, *WorkflowDefinition) error
+	ListWorkflowDefinitions(context.Context, *ListWorkflowDefinitionsRequest, *ListWorkflowDefinitionsResponse) error
+	GetWorkflowDefinition(context.Context, *WorkflowDefinitionRef, *WorkflowDefinition) error
+	ExportWorkflow(context.Context, *ExportRequest, *ExportResponse) error
 }
 
 func RegisterSagawfHandler(s server.Server, hdlr SagawfHandler, opts ...server.HandlerOption) error {
 	type sagawf interface {
 		RunWorkflow(ctx context.Context, in *WorkflowRequest, out *WorkflowResponse) error
+		StartWorkflow(ctx context.Context, in *WorkflowRequest, out *WorkflowRef) error
+		GetWorkflowStatus(ctx context.Context, in *WorkflowRef, out *WorkflowStatus) error
+		WatchWorkflow(ctx context.Context, stream server.Stream) error
+		RedriveWorkflow(ctx context.Context, in *WorkflowRef, out *WorkflowRef) error
+		CancelWorkflow(ctx context.Context, in *WorkflowRef, out *WorkflowRef) error
+		PauseWorkflow(ctx context.Context, in *WorkflowRef, out *WorkflowRef) error
+		ResumeWorkflow(ctx context.Context, in *WorkflowRef, out *WorkflowRef) error
+		ForceCompleteOperation(ctx context.Context, in *OperationRequest, out *WorkflowRef) error
+		SkipOperation(ctx context.Context, in *OperationRequest, out *WorkflowRef) error
+		RegisterWorkflowDefinition(ctx context.Context, in *WorkflowDefinition, out *WorkflowDefinition) error
+		ListWorkflowDefinitions(ctx context.Context, in *ListWorkflowDefinitionsRequest, out *ListWorkflowDefinitionsResponse) error
+		GetWorkflowDefinition(ctx context.Context, in *WorkflowDefinitionRef, out *WorkflowDefinition) error
+		ExportWorkflow(ctx context.Context, in *ExportRequest, out *ExportResponse) error
 	}
 	type Sagawf struct {
 		sagawf
@@ -85,3 +298,91 @@ type sagawfHandler struct {
 func (h *sagawfHandler) RunWorkflow(ctx context.Context, in *WorkflowRequest, out *WorkflowResponse) error {
 	return h.SagawfHandler.RunWorkflow(ctx, in, out)
 }
+
+func (h *sagawfHandler) StartWorkflow(ctx context.Context, in *WorkflowRequest, out *WorkflowRef) error {
+	return h.SagawfHandler.StartWorkflow(ctx, in, out)
+}
+
+func (h *sagawfHandler) GetWorkflowStatus(ctx context.Context, in *WorkflowRef, out *WorkflowStatus) error {
+	return h.SagawfHandler.GetWorkflowStatus(ctx, in, out)
+}
+
+func (h *sagawfHandler) WatchWorkflow(ctx context.Context, stream server.Stream) error {
+	m := new(WorkflowRef)
+	if err := stream.Recv(m); err != nil {
+		return err
+	}
+	return h.SagawfHandler.WatchWorkflow(ctx, m, &sagawfWatchWorkflowStream{stream})
+}
+
+type Sagawf_WatchWorkflowStream interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Send(*WorkflowEvent) error
+}
+
+type sagawfWatchWorkflowStream struct {
+	stream server.Stream
+}
+
+func (x *sagawfWatchWorkflowStream) Close() error {
+	return x.stream.Close()
+}
+
+func (x *sagawfWatchWorkflowStream) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *sagawfWatchWorkflowStream) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *sagawfWatchWorkflowStream) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *sagawfWatchWorkflowStream) Send(m *WorkflowEvent) error {
+	return x.stream.Send(m)
+}
+
+func (h *sagawfHandler) RedriveWorkflow(ctx context.Context, in *WorkflowRef, out *WorkflowRef) error {
+	return h.SagawfHandler.RedriveWorkflow(ctx, in, out)
+}
+
+func (h *sagawfHandler) CancelWorkflow(ctx context.Context, in *WorkflowRef, out *WorkflowRef) error {
+	return h.SagawfHandler.CancelWorkflow(ctx, in, out)
+}
+
+func (h *sagawfHandler) PauseWorkflow(ctx context.Context, in *WorkflowRef, out *WorkflowRef) error {
+	return h.SagawfHandler.PauseWorkflow(ctx, in, out)
+}
+
+func (h *sagawfHandler) ResumeWorkflow(ctx context.Context, in *WorkflowRef, out *WorkflowRef) error {
+	return h.SagawfHandler.ResumeWorkflow(ctx, in, out)
+}
+
+func (h *sagawfHandler) ForceCompleteOperation(ctx context.Context, in *OperationRequest, out *WorkflowRef) error {
+	return h.SagawfHandler.ForceCompleteOperation(ctx, in, out)
+}
+
+func (h *sagawfHandler) SkipOperation(ctx context.Context, in *OperationRequest, out *WorkflowRef) error {
+	return h.SagawfHandler.SkipOperation(ctx, in, out)
+}
+
+func (h *sagawfHandler) RegisterWorkflowDefinition(ctx context.Context, in *WorkflowDefinition, out *WorkflowDefinition) error {
+	return h.SagawfHandler.RegisterWorkflowDefinition(ctx, in, out)
+}
+
+func (h *sagawfHandler) ListWorkflowDefinitions(ctx context.Context, in *ListWorkflowDefinitionsRequest, out *ListWorkflowDefinitionsResponse) error {
+	return h.SagawfHandler.ListWorkflowDefinitions(ctx, in, out)
+}
+
+func (h *sagawfHandler) GetWorkflowDefinition(ctx context.Context, in *WorkflowDefinitionRef, out *WorkflowDefinition) error {
+	return h.SagawfHandler.GetWorkflowDefinition(ctx, in, out)
+}
+
+func (h *sagawfHandler) ExportWorkflow(ctx context.Context, in *ExportRequest, out *ExportResponse) error {
+	return h.SagawfHandler.ExportWorkflow(ctx, in, out)
+}
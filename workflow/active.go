@@ -0,0 +1,44 @@
+package workflow
+
+import (
+	"context"
+	"strings"
+
+	mc "go-micro.dev/v4/cache"
+)
+
+// every active workflow has its own key so starting and ending workflows do not contend for a shared index
+const activeKeyPrefix = "workflow:active:"
+
+func getActiveKey(id string) string {
+	return activeKeyPrefix + id
+}
+
+func setActive(cache Cache, id string, isActive bool) error {
+	ctx := context.Background()
+
+	if isActive {
+		return cache.Set(ctx, getActiveKey(id), true)
+	}
+
+	err := cache.Remove(ctx, getActiveKey(id))
+	if err == mc.ErrKeyNotFound {
+		return nil
+	}
+
+	return err
+}
+
+func GetActiveIDs(cache Cache) ([]string, error) {
+	keys, err := cache.List(context.Background(), activeKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []string{}
+	for _, key := range keys {
+		result = append(result, strings.TrimPrefix(key, activeKeyPrefix))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,140 @@
+package workflow
+
+import "time"
+
+type StepOption func(op *Operation)
+
+func WithRetry(policy RetryPolicy) StepOption {
+	return func(op *Operation) {
+		op.Retry = &policy
+	}
+}
+
+func WithCompensationRetry(policy RetryPolicy) StepOption {
+	return func(op *Operation) {
+		op.CompensationRetry = &policy
+	}
+}
+
+// WithCondition takes the step only if the condition over the workflow data is true
+func WithCondition(condition string) StepOption {
+	return func(op *Operation) {
+		op.Condition = condition
+	}
+}
+
+// WithForEach starts the step for every item of the array found by the path
+func WithForEach(items string) StepOption {
+	return func(op *Operation) {
+		op.ForEach = items
+	}
+}
+
+// WithWorkflow runs the version of the registered definition as the step, the latest one if the version is 0
+func WithWorkflow(name string, version int) StepOption {
+	return func(op *Operation) {
+		op.Workflow = name
+		op.WorkflowVersion = version
+	}
+}
+
+// WithExecutor runs the step by the executor of the type, e.g. an http webhook at the service url
+func WithExecutor(executorType string, service string) StepOption {
+	return func(op *Operation) {
+		op.Type = executorType
+		op.Service = service
+	}
+}
+
+func WithTimeout(d time.Duration) StepOption {
+	return func(op *Operation) {
+		op.Timeout = d
+	}
+}
+
+// Builder assembles the workflow graph, the graph is validated by Build
+type Builder struct {
+	w Workflow
+}
+
+func NewBuilder(name string) *Builder {
+	return &Builder{
+		w: Workflow{
+			Name:       name,
+			Operations: []Operation{},
+		},
+	}
+}
+
+func (b *Builder) Start(vertex string) *Builder {
+	b.w.Start = vertex
+	return b
+}
+
+func (b *Builder) End(vertex string) *Builder {
+	b.w.End = vertex
+	return b
+}
+
+func (b *Builder) Timeout(d time.Duration) *Builder {
+	b.w.Timeout = d
+	return b
+}
+
+func (b *Builder) Metadata(key string, value string) *Builder {
+	if b.w.Metadata == nil {
+		b.w.Metadata = make(map[string]string)
+	}
+
+	b.w.Metadata[key] = value
+	return b
+}
+
+func (b *Builder) Payload(payload interface{}) *Builder {
+	b.w.Payload = payload
+	return b
+}
+
+// Step adds the operation moving the workflow from one vertex to another
+func (b *Builder) Step(name string, from string, to string, opts ...StepOption) *Builder {
+	op := Operation{
+		Name: name,
+		From: from,
+		To:   to,
+	}
+
+	for _, o := range opts {
+		o(&op)
+	}
+
+	b.w.Operations = append(b.w.Operations, op)
+	return b
+}
+
+// Parallel adds the operations executed concurrently between the same vertices
+func (b *Builder) Parallel(from string, to string, names ...string) *Builder {
+	for _, name := range names {
+		b.Step(name, from, to)
+	}
+
+	return b
+}
+
+// Build returns the copy of the assembled workflow or the validation error
+func (b *Builder) Build() (Workflow, error) {
+	w := b.w
+	w.Operations = append([]Operation{}, b.w.Operations...)
+	if b.w.Metadata != nil {
+		w.Metadata = make(map[string]string)
+		for key, value := range b.w.Metadata {
+			w.Metadata[key] = value
+		}
+	}
+
+	err := Validate(w)
+	if err != nil {
+		return Workflow{}, err
+	}
+
+	return w, nil
+}
@@ -0,0 +1,56 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	retry := RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Second,
+	}
+
+	w, err := NewBuilder("order").
+		Start("s1").
+		Step("reserve", "s1", "s2", WithRetry(retry), WithTimeout(time.Second)).
+		Parallel("s2", "s3", "charge", "notify").
+		End("s3").
+		Metadata("owner", "shop").
+		Build()
+
+	assert.NoError(t, err)
+	assert.Equal(t, Workflow{
+		Name:  "order",
+		Start: "s1",
+		End:   "s3",
+		Operations: []Operation{
+			{Name: "reserve", From: "s1", To: "s2", Retry: &retry, Timeout: time.Second},
+			{Name: "charge", From: "s2", To: "s3"},
+			{Name: "notify", From: "s2", To: "s3"},
+		},
+		Metadata: map[string]string{"owner": "shop"},
+	}, w)
+}
+
+func TestBuilderValidation(t *testing.T) {
+	_, err := NewBuilder("order").
+		Start("s1").
+		Step("reserve", "s1", "s2").
+		Step("charge", "s2", "s1").
+		End("s2").
+		Build()
+
+	assert.IsType(t, &ValidationError{}, err)
+	assert.Equal(t, ISSUE_CYCLE, err.(*ValidationError).Issues[0].Code)
+
+	_, err = NewBuilder("order").
+		Start("s1").
+		Step("reserve", "s1", "s2").
+		End("s3").
+		Build()
+
+	assert.Equal(t, ISSUE_UNREACHABLE_END, err.(*ValidationError).Issues[0].Code)
+}
@@ -3,24 +3,50 @@ package workflow
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
 
 	mc "go-micro.dev/v4/cache"
 )
 
+const maxUpdateAttempts = 100
+
+var ErrVersionConflict = errors.New("cache value has been modified concurrently")
+
+type entry struct {
+	Value   string
+	Version uint64
+}
+
 type cache struct {
+	sync.Mutex
 	cache mc.Cache
+	// keys are tracked because the go-micro cache can not be enumerated
+	keys map[string]bool
 }
 
 func NewCache(opts ...mc.Option) Cache {
 	c := mc.NewCache(opts...)
-	return &cache{c}
+	return &cache{
+		cache: c,
+		keys:  make(map[string]bool),
+	}
 }
 
 type Cache interface {
 	Set(ctx context.Context, key string, value interface{}) error
 	Get(ctx context.Context, key string) (string, error)
 	Remove(ctx context.Context, key string) error
+	// GetWithVersion returns the value with its version, the version of a missing key is 0
+	GetWithVersion(ctx context.Context, key string) (string, uint64, error)
+	// CompareAndSet stores the value only if the key still has the expected version
+	CompareAndSet(ctx context.Context, key string, value interface{}, version uint64) error
+	// CompareAndRemove removes the key only if it still has the expected version
+	CompareAndRemove(ctx context.Context, key string, version uint64) error
+	// List returns the sorted keys with the prefix
+	List(ctx context.Context, prefix string) ([]string, error)
 }
 
 func (c *cache) Set(ctx context.Context, key string, value interface{}) error {
@@ -29,20 +55,132 @@ func (c *cache) Set(ctx context.Context, key string, value interface{}) error {
 		return err
 	}
 
-	return c.cache.Context(ctx).Put(key, string(rawValue), 0)
+	c.Lock()
+	defer c.Unlock()
+
+	_, version, err := c.get(ctx, key)
+	if err != nil && err != mc.ErrKeyNotFound {
+		return err
+	}
+
+	return c.put(ctx, key, string(rawValue), version)
 }
 
 func (c *cache) Get(ctx context.Context, key string) (string, error) {
-	value, _, err := c.cache.Context(ctx).Get(key)
+	value, _, err := c.GetWithVersion(ctx, key)
+	return value, err
+}
+
+func (c *cache) Remove(ctx context.Context, key string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	delete(c.keys, key)
+	return c.cache.Context(ctx).Delete(key)
+}
+
+func (c *cache) List(ctx context.Context, prefix string) ([]string, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	return listKeys(c.keys, prefix), nil
+}
+
+func listKeys(keys map[string]bool, prefix string) []string {
+	result := []string{}
+	for key := range keys {
+		if strings.HasPrefix(key, prefix) {
+			result = append(result, key)
+		}
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+func (c *cache) GetWithVersion(ctx context.Context, key string) (string, uint64, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.get(ctx, key)
+}
+
+func (c *cache) CompareAndSet(ctx context.Context, key string, value interface{}, version uint64) error {
+	rawValue, err := json.Marshal(value)
 	if err != nil {
-		return "", err
+		return err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	_, current, err := c.get(ctx, key)
+	if err != nil && err != mc.ErrKeyNotFound {
+		return err
+	}
+
+	if current != version {
+		return ErrVersionConflict
 	}
 
-	result := fmt.Sprintf("%v", value)
-	return result, nil
+	return c.put(ctx, key, string(rawValue), version)
 }
 
-func (c *cache) Remove(ctx context.Context, key string) error {
+func (c *cache) CompareAndRemove(ctx context.Context, key string, version uint64) error {
+	c.Lock()
+	defer c.Unlock()
+
+	_, current, err := c.get(ctx, key)
+	if err != nil && err != mc.ErrKeyNotFound {
+		return err
+	}
 
+	if current != version {
+		return ErrVersionConflict
+	}
+
+	delete(c.keys, key)
 	return c.cache.Context(ctx).Delete(key)
 }
+
+func (c *cache) get(ctx context.Context, key string) (string, uint64, error) {
+	value, _, err := c.cache.Context(ctx).Get(key)
+	if err != nil {
+		return "", 0, err
+	}
+
+	e := value.(entry)
+	return e.Value, e.Version, nil
+}
+
+func (c *cache) put(ctx context.Context, key string, value string, version uint64) error {
+	c.keys[key] = true
+	return c.cache.Context(ctx).Put(key, entry{
+		Value:   value,
+		Version: version + 1,
+	}, 0)
+}
+
+// updateValue applies the update to the current value of the key and retries it on concurrent modification
+func updateValue(cache Cache, key string, update func(raw string, found bool) (interface{}, error)) error {
+	ctx := context.Background()
+
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		raw, version, err := cache.GetWithVersion(ctx, key)
+		if err != nil && err != mc.ErrKeyNotFound {
+			return err
+		}
+
+		value, err := update(raw, err == nil)
+		if err != nil {
+			return err
+		}
+
+		err = cache.CompareAndSet(ctx, key, value, version)
+		if err != ErrVersionConflict {
+			return err
+		}
+	}
+
+	return ErrVersionConflict
+}
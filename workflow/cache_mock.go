@@ -3,17 +3,21 @@ package workflow
 import (
 	"context"
 	"encoding/json"
+	"sync"
 
 	mc "go-micro.dev/v4/cache"
 )
 
 type CacheMock struct {
-	values map[string]string
+	sync.Mutex
+	values   map[string]string
+	versions map[string]uint64
 }
 
 func NewCacheMock() *CacheMock {
 	return &CacheMock{
-		values: make(map[string]string),
+		values:   make(map[string]string),
+		versions: make(map[string]uint64),
 	}
 }
 
@@ -23,26 +27,88 @@ func (c *CacheMock) Set(ctx context.Context, key string, value interface{}) erro
 		return err
 	}
 
+	c.Lock()
+	defer c.Unlock()
+
 	c.values[key] = string(rawValue)
+	c.versions[key]++
 	return nil
 }
 
 func (c *CacheMock) Get(ctx context.Context, key string) (string, error) {
+	value, _, err := c.GetWithVersion(ctx, key)
+	return value, err
+}
+
+func (c *CacheMock) Remove(ctx context.Context, key string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	delete(c.values, key)
+	delete(c.versions, key)
+
+	return nil
+}
+
+func (c *CacheMock) GetWithVersion(ctx context.Context, key string) (string, uint64, error) {
+	c.Lock()
+	defer c.Unlock()
+
 	value, found := c.values[key]
 	if !found {
-		return "", mc.ErrKeyNotFound
+		return "", 0, mc.ErrKeyNotFound
 	}
 
-	return value, nil
+	return value, c.versions[key], nil
 }
 
-func (c *CacheMock) Remove(ctx context.Context, key string) error {
-	delete(c.values, key)
+func (c *CacheMock) CompareAndSet(ctx context.Context, key string, value interface{}, version uint64) error {
+	rawValue, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
 
+	c.Lock()
+	defer c.Unlock()
+
+	if c.versions[key] != version {
+		return ErrVersionConflict
+	}
+
+	c.values[key] = string(rawValue)
+	c.versions[key]++
 	return nil
 }
 
+func (c *CacheMock) CompareAndRemove(ctx context.Context, key string, version uint64) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.versions[key] != version {
+		return ErrVersionConflict
+	}
+
+	delete(c.values, key)
+	delete(c.versions, key)
+	return nil
+}
+
+func (c *CacheMock) List(ctx context.Context, prefix string) ([]string, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	keys := make(map[string]bool)
+	for key := range c.values {
+		keys[key] = true
+	}
+
+	return listKeys(keys, prefix), nil
+}
+
 func (c *CacheMock) Has(key string, value interface{}) bool {
+	c.Lock()
+	defer c.Unlock()
+
 	if raw, found := c.values[key]; found {
 		pattern, err := json.Marshal(value)
 		if err != nil {
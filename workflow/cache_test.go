@@ -0,0 +1,146 @@
+package workflow
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var caches = map[string]func(t *testing.T) Cache{
+	"memory cache": func(t *testing.T) Cache {
+		return NewCache()
+	},
+	"file cache": func(t *testing.T) Cache {
+		cache, err := NewFileCache(filepath.Join(t.TempDir(), "sagawf.db"))
+		assert.NoError(t, err)
+		return cache
+	},
+	"cache mock": func(t *testing.T) Cache {
+		return NewCacheMock()
+	},
+}
+
+func TestCompareAndSet(t *testing.T) {
+	for name, create := range caches {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			cache := create(t)
+
+			// the missing key can be created with version 0 only
+			assert.Equal(t, ErrVersionConflict, cache.CompareAndSet(ctx, "key", "v1", 1))
+			assert.NoError(t, cache.CompareAndSet(ctx, "key", "v1", 0))
+
+			value, version, err := cache.GetWithVersion(ctx, "key")
+			assert.NoError(t, err)
+			assert.Equal(t, `"v1"`, value)
+
+			assert.NoError(t, cache.Set(ctx, "key", "v2"))
+
+			// the stale version is rejected
+			assert.Equal(t, ErrVersionConflict, cache.CompareAndSet(ctx, "key", "v3", version))
+
+			value, version, err = cache.GetWithVersion(ctx, "key")
+			assert.NoError(t, err)
+			assert.Equal(t, `"v2"`, value)
+
+			assert.NoError(t, cache.CompareAndSet(ctx, "key", "v3", version))
+
+			value, err = cache.Get(ctx, "key")
+			assert.NoError(t, err)
+			assert.Equal(t, `"v3"`, value)
+		})
+	}
+}
+
+func TestCompareAndRemove(t *testing.T) {
+	for name, create := range caches {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			cache := create(t)
+
+			assert.NoError(t, cache.Set(ctx, "key", "v1"))
+			_, version, err := cache.GetWithVersion(ctx, "key")
+			assert.NoError(t, err)
+
+			assert.NoError(t, cache.Set(ctx, "key", "v2"))
+
+			// the stale version is rejected
+			assert.Equal(t, ErrVersionConflict, cache.CompareAndRemove(ctx, "key", version))
+
+			_, version, err = cache.GetWithVersion(ctx, "key")
+			assert.NoError(t, err)
+			assert.NoError(t, cache.CompareAndRemove(ctx, "key", version))
+
+			_, err = cache.Get(ctx, "key")
+			assert.Error(t, err)
+
+			keys, err := cache.List(ctx, "key")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{}, keys)
+		})
+	}
+}
+
+func TestCacheList(t *testing.T) {
+	for name, create := range caches {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			cache := create(t)
+
+			for _, key := range []string{"workflow:state:2", "workflow:index", "workflow:state:1", "workflow:state:1.pay"} {
+				assert.NoError(t, cache.Set(ctx, key, key))
+			}
+			assert.NoError(t, cache.Remove(ctx, "workflow:state:2"))
+
+			keys, err := cache.List(ctx, "workflow:state:")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"workflow:state:1", "workflow:state:1.pay"}, keys)
+
+			keys, err = cache.List(ctx, "workflow:definition:")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{}, keys)
+		})
+	}
+}
+
+func TestConcurrentStateUpdate(t *testing.T) {
+	cache := NewCache()
+
+	s := state{
+		ID: "1",
+	}
+	assert.NoError(t, s.init(cache, "s1", nil))
+
+	ops := []Operation{}
+	for _, name := range []string{"op1", "op2", "op3", "op4", "op5", "op6", "op7", "op8"} {
+		ops = append(ops, Operation{
+			Name: name,
+			From: "s1",
+			To:   "s2",
+		})
+	}
+
+	var wg sync.WaitGroup
+	for _, op := range ops {
+		wg.Add(1)
+		go func(op Operation) {
+			defer wg.Done()
+
+			s := state{
+				ID: "1",
+			}
+			assert.NoError(t, s.update(cache, func(s *state) {
+				addOp(s.Done, op, false)
+				s.setData(op.To, op.Name, op.Name)
+			}))
+		}(op)
+	}
+	wg.Wait()
+
+	assert.NoError(t, s.load(cache))
+	assert.Len(t, s.Done, len(ops))
+	assert.Len(t, s.Data["s2"], len(ops))
+}
@@ -0,0 +1,144 @@
+package workflow
+
+import "fmt"
+
+func (p *processor) onCompensationFailure(w Workflow, op OperationPayload) error {
+	p.workflow = w
+
+	p.state = state{
+		ID: op.ID,
+	}
+
+	isProcessed := false
+	isRetried := false
+	err := p.commit(func(s *state) {
+		isProcessed = s.isProcessed(op)
+		if isProcessed {
+			return
+		}
+		s.setProcessed(op)
+
+		removeOp(s.InProgress, op.Operation, true)
+
+		key := op.Operation.getKey(true)
+		if !op.Operation.isMap() && op.Operation.CompensationRetry.canRetry(s.Attempts[key]) {
+			s.Attempts[key]++
+			isRetried = true
+			addOp(s.InProgress, op.Operation, true)
+			s.setStarted(op.Operation, true)
+			p.retryOperation(s, op.Operation, true, s.Attempts[key])
+		} else {
+			// the failed compensation is never spawned again until the workflow is redriven
+			isRetried = false
+			addOp(s.Failed, op.Operation, true)
+		}
+	})
+
+	if err != nil || isProcessed || isRetried {
+		return err
+	}
+
+	// the compensations independent of the failed one are still spawned
+	err = p.resolve()
+	if err != nil {
+		return err
+	}
+
+	return p.checkStuck()
+}
+
+// checkStuck marks the workflow stuck once no compensation is in progress besides the failed ones
+func (p *processor) checkStuck() error {
+	if !p.state.IsRollback || !p.state.hasFailedCompensations() {
+		return nil
+	}
+
+	isStuck := false
+	err := p.commit(func(s *state) {
+		isStuck = !s.IsStuck && !s.Completed && s.hasFailedCompensations() && !s.hasCompensationsInProgress()
+		if isStuck {
+			s.IsStuck = true
+			p.stuckWorkflow(s)
+		}
+	})
+
+	if err != nil || !isStuck {
+		return err
+	}
+
+	return setActive(p.cache, p.state.ID, false)
+}
+
+func (s *state) hasCompensationsInProgress() bool {
+	for key, op := range s.InProgress {
+		if key == op.getKey(true) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *processor) stuckWorkflow(s *state) {
+	payload := WorkflowPayload{
+		ID:         s.ID,
+		IsRollback: s.IsRollback,
+		IsStuck:    true,
+		Name:       p.workflow.Name,
+		Data:       s.Data,
+	}
+
+	s.enqueue(WORKFLOW_STUCK, payload)
+
+	// the parent operation is failed instead of waiting for the stuck child
+	p.notifyParent(s)
+}
+
+// redrive clears the failed compensations so they are spawned again
+func (s *state) redrive(w Workflow) {
+	s.IsStuck = false
+	for key, op := range s.Failed {
+		if key == op.getKey(true) {
+			delete(s.Failed, key)
+			delete(s.Attempts, key)
+			s.forget(op, true)
+
+			// the map operation is spawned again to compensate its remaining items
+			if parent, found := w.getOperation(op.Parent); found {
+				removeOp(s.InProgress, parent, true)
+				s.forget(parent, true)
+			}
+		}
+	}
+}
+
+// Redrive spawns the failed compensations of the stuck workflow again
+func (p *processor) Redrive(w Workflow, id string) error {
+	p.workflow = w
+
+	p.state = state{
+		ID: id,
+	}
+	err := p.state.load(p.cache)
+	if err != nil {
+		return err
+	}
+
+	if !p.state.IsStuck {
+		return fmt.Errorf("workflow %s is not stuck", id)
+	}
+
+	err = p.state.update(p.cache, func(s *state) {
+		s.redrive(w)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = setActive(p.cache, id, true)
+	if err != nil {
+		return err
+	}
+
+	return p.resolve()
+}
@@ -0,0 +1,363 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// condition is a boolean expression over the workflow data, e.g.
+//
+//	s2.payment.method == "card" && s2.payment.amount > 100
+//
+// a path starts with the vertex and the operation name and continues with the fields of the operation payload
+type condition interface {
+	eval(data map[string]map[string]interface{}) interface{}
+}
+
+type literalCondition struct {
+	value interface{}
+}
+
+type pathCondition struct {
+	segments []string
+}
+
+type notCondition struct {
+	operand condition
+}
+
+type binaryCondition struct {
+	op    string
+	left  condition
+	right condition
+}
+
+func (l literalCondition) eval(data map[string]map[string]interface{}) interface{} {
+	return l.value
+}
+
+func (p pathCondition) eval(data map[string]map[string]interface{}) interface{} {
+	ops, found := data[p.segments[0]]
+	if !found || len(p.segments) < 2 {
+		return nil
+	}
+
+	value, found := ops[p.segments[1]]
+	if !found {
+		return nil
+	}
+
+	for _, segment := range p.segments[2:] {
+		value = getField(value, segment)
+	}
+
+	return normalize(value)
+}
+
+func getField(value interface{}, field string) interface{} {
+	// operation payloads are often passed as json strings
+	if raw, ok := value.(string); ok {
+		var decoded interface{}
+		if json.Unmarshal([]byte(raw), &decoded) != nil {
+			return nil
+		}
+		value = decoded
+	}
+
+	if m, ok := value.(map[string]interface{}); ok {
+		return m[field]
+	}
+
+	return nil
+}
+
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	default:
+		return v
+	}
+}
+
+func isTrue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case float64:
+		return v != 0
+	default:
+		return true
+	}
+}
+
+func (u notCondition) eval(data map[string]map[string]interface{}) interface{} {
+	return !isTrue(u.operand.eval(data))
+}
+
+func (b binaryCondition) eval(data map[string]map[string]interface{}) interface{} {
+	switch b.op {
+	case "&&":
+		return isTrue(b.left.eval(data)) && isTrue(b.right.eval(data))
+	case "||":
+		return isTrue(b.left.eval(data)) || isTrue(b.right.eval(data))
+	}
+
+	left := b.left.eval(data)
+	right := b.right.eval(data)
+
+	switch b.op {
+	case "==":
+		return left == right
+	case "!=":
+		return left != right
+	}
+
+	// values of different types are not ordered
+	switch l := left.(type) {
+	case float64:
+		if r, ok := right.(float64); ok {
+			return compare(b.op, l < r, l == r)
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			return compare(b.op, l < r, l == r)
+		}
+	}
+
+	return false
+}
+
+func compare(op string, less bool, equal bool) bool {
+	switch op {
+	case "<":
+		return less
+	case "<=":
+		return less || equal
+	case ">":
+		return !less && !equal
+	default:
+		return !less
+	}
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func parseCondition(expression string) (condition, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	result, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %s in condition %q", p.tokens[p.pos], expression)
+	}
+
+	return result, nil
+}
+
+// evalCondition returns true for an empty expression
+func evalCondition(expression string, data map[string]map[string]interface{}) (bool, error) {
+	if expression == "" {
+		return true, nil
+	}
+
+	c, err := parseCondition(expression)
+	if err != nil {
+		return false, err
+	}
+
+	return isTrue(c.eval(data)), nil
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+
+	return ""
+}
+
+func (p *parser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *parser) parseOr() (condition, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.peek() == "||" {
+		p.next()
+
+		var right condition
+		right, err = p.parseAnd()
+		left = binaryCondition{op: "||", left: left, right: right}
+	}
+
+	return left, err
+}
+
+func (p *parser) parseAnd() (condition, error) {
+	left, err := p.parseNot()
+	for err == nil && p.peek() == "&&" {
+		p.next()
+
+		var right condition
+		right, err = p.parseNot()
+		left = binaryCondition{op: "&&", left: left, right: right}
+	}
+
+	return left, err
+}
+
+func (p *parser) parseNot() (condition, error) {
+	if p.peek() == "!" {
+		p.next()
+
+		operand, err := p.parseNot()
+		return notCondition{operand: operand}, err
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (condition, error) {
+	left, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	switch op := p.peek(); op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		p.next()
+
+		right, err := p.parseValue()
+		return binaryCondition{op: op, left: left, right: right}, err
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseValue() (condition, error) {
+	token := p.next()
+
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of condition")
+	case token == "(":
+		result, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing ) in condition")
+		}
+
+		return result, nil
+	case token == "true" || token == "false":
+		return literalCondition{value: token == "true"}, nil
+	case token == "null":
+		return literalCondition{value: nil}, nil
+	case token[0] == '"':
+		value, err := strconv.Unquote(token)
+		return literalCondition{value: value}, err
+	case token[0] == '-' || unicode.IsDigit(rune(token[0])):
+		value, err := strconv.ParseFloat(token, 64)
+		return literalCondition{value: value}, err
+	case isIdentifier(rune(token[0])):
+		return pathCondition{segments: strings.Split(token, ".")}, nil
+	default:
+		return nil, fmt.Errorf("unexpected %s in condition", token)
+	}
+}
+
+func isIdentifier(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func tokenize(expression string) ([]string, error) {
+	tokens := []string{}
+	runes := []rune(expression)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				if runes[j] == '\\' {
+					j++
+				}
+				j++
+			}
+
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated string in condition %q", expression)
+			}
+
+			tokens = append(tokens, string(runes[i:j+1]))
+			i = j + 1
+		case r == '-' || unicode.IsDigit(r):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		case isIdentifier(r):
+			j := i + 1
+			for j < len(runes) && (isIdentifier(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '.' || runes[j] == '-') {
+				j++
+			}
+
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		case strings.ContainsRune("()", r):
+			tokens = append(tokens, string(r))
+			i++
+		default:
+			if i+1 < len(runes) {
+				switch token := string(runes[i : i+2]); token {
+				case "==", "!=", "<=", ">=", "&&", "||":
+					tokens = append(tokens, token)
+					i += 2
+					continue
+				}
+			}
+
+			switch r {
+			case '<', '>', '!':
+				tokens = append(tokens, string(r))
+				i++
+			default:
+				return nil, fmt.Errorf("unexpected %c in condition %q", r, expression)
+			}
+		}
+	}
+
+	return tokens, nil
+}
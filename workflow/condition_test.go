@@ -0,0 +1,57 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvalCondition(t *testing.T) {
+	data := map[string]map[string]interface{}{
+		"s1": {
+			"input": "1",
+		},
+		"s2": {
+			"pay":    `{"method":"card","amount":150,"confirmed":true}`,
+			"notify": map[string]interface{}{"sent": false},
+		},
+	}
+
+	var tests = map[string]bool{
+		"":                                    true,
+		`s2.pay.method == "card"`:             true,
+		`s2.pay.method != "card"`:             false,
+		`s2.pay.amount > 100`:                 true,
+		`s2.pay.amount <= 100`:                false,
+		`s2.pay.confirmed`:                    true,
+		`!s2.notify.sent`:                     true,
+		`s2.pay.missing == null`:              true,
+		`s2.pay.method == "cash" || s1.input`: true,
+		`(s2.pay.amount >= 150) && !(s2.pay.method < "b")`: true,
+		`s2.pay.method > 1`: false,
+		`s3.op.field`:       false,
+	}
+
+	for expression, expected := range tests {
+		t.Run(expression, func(t *testing.T) {
+			result, err := evalCondition(expression, data)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, result)
+		})
+	}
+}
+
+func TestParseConditionErrors(t *testing.T) {
+	for _, expression := range []string{
+		`s2.pay.method ==`,
+		`(s2.pay.amount > 1`,
+		`s2.pay.method = "card"`,
+		`"card`,
+		`s2.pay.amount > 1 1`,
+	} {
+		t.Run(expression, func(t *testing.T) {
+			_, err := parseCondition(expression)
+			assert.Error(t, err)
+		})
+	}
+}
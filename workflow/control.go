@@ -0,0 +1,198 @@
+package workflow
+
+import (
+	"fmt"
+
+	mc "go-micro.dev/v4/cache"
+)
+
+// Cancel stops spawning the operations of the workflow and compensates the completed ones,
+// the operations in progress are compensated once they are completed and the running child workflows are cancelled
+func (p *processor) Cancel(w Workflow, id string) error {
+	p.workflow = w
+
+	p.state = state{
+		ID: id,
+	}
+	err := p.state.update(p.cache, func(s *state) {
+		if s.Completed {
+			s.err = fmt.Errorf("workflow %s is completed", id)
+			return
+		}
+
+		s.IsCancelled = true
+		s.IsPaused = false
+		s.IsRollback = true
+	})
+	if err != nil {
+		return err
+	}
+
+	err = p.cancelChildren()
+	if err != nil {
+		return err
+	}
+
+	return p.resolve()
+}
+
+func (p *processor) cancelChildren() error {
+	for _, op := range p.workflow.Operations {
+		if op.isWorkflow() && hasOp(p.state.InProgress, op, false) {
+			err := p.cancelChild(p.state.Children[op.Name])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// cancelChild cancels the running child workflow, its rollback fails the parent operation
+func (p *processor) cancelChild(id string) error {
+	s := state{
+		ID: id,
+	}
+	err := s.load(p.cache)
+	if err == mc.ErrKeyNotFound {
+		// the child workflow is not started yet, it is rollbacked when its start fails the parent operation
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if s.Completed || s.IsCancelled {
+		return nil
+	}
+
+	child, err := LoadWorkflow(p.cache, id)
+	if err != nil {
+		return err
+	}
+
+	return NewProcessor(p.cache, p.producer).Cancel(child, id)
+}
+
+// Pause stops spawning the operations of the workflow, the operations in progress are completed
+func (p *processor) Pause(w Workflow, id string) error {
+	p.workflow = w
+
+	p.state = state{
+		ID: id,
+	}
+	return p.state.update(p.cache, func(s *state) {
+		if s.Completed {
+			s.err = fmt.Errorf("workflow %s is completed", id)
+			return
+		}
+
+		s.IsPaused = true
+	})
+}
+
+// Continue resolves the paused workflow again
+func (p *processor) Continue(w Workflow, id string) error {
+	p.workflow = w
+
+	p.state = state{
+		ID: id,
+	}
+	err := p.state.update(p.cache, func(s *state) {
+		if !s.IsPaused {
+			s.err = fmt.Errorf("workflow %s is not paused", id)
+			return
+		}
+
+		s.IsPaused = false
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.resolve()
+}
+
+// ForceComplete completes the operation in progress or the failed compensation with the payload
+func (p *processor) ForceComplete(w Workflow, id string, name string, isRollback bool, payload interface{}) error {
+	return p.settle(w, id, name, isRollback, func(s *state, op Operation) {
+		addOp(s.Done, op, isRollback)
+		s.setData(op.To, op.Name, payload)
+	})
+}
+
+// Skip ends the operation in progress as the branch not taken or the compensation as completed without its result
+func (p *processor) Skip(w Workflow, id string, name string, isRollback bool) error {
+	return p.settle(w, id, name, isRollback, func(s *state, op Operation) {
+		if isRollback {
+			addOp(s.Done, op, true)
+		} else {
+			addOp(s.Skipped, op, false)
+		}
+	})
+}
+
+// settle ends the operation manually and resolves the workflow, the late result of the operation is ignored
+func (p *processor) settle(w Workflow, id string, name string, isRollback bool, end func(s *state, op Operation)) error {
+	op, found := w.getOperation(name)
+	if !found {
+		return fmt.Errorf("operation %s is not found", name)
+	}
+
+	// the items of the map operation are settled with it
+	if op.isMap() {
+		return fmt.Errorf("map operation %s can not be settled", name)
+	}
+
+	p.workflow = w
+
+	p.state = state{
+		ID: id,
+	}
+
+	key := op.getKey(isRollback)
+	isUnstuck := false
+	err := p.state.update(p.cache, func(s *state) {
+		_, inProgress := s.InProgress[key]
+		_, failed := s.Failed[key]
+		if !inProgress && !(isRollback && failed) {
+			s.err = fmt.Errorf("operation %s is not in progress", name)
+			return
+		}
+
+		removeOp(s.InProgress, op, isRollback)
+		removeOp(s.Failed, op, isRollback)
+		delete(s.Deadlines, key)
+		delete(s.Started, key)
+		delete(s.Retries, key)
+		addOp(s.Settled, op, isRollback)
+		end(s, op)
+
+		if s.IsStuck && !s.hasFailedCompensations() {
+			s.IsStuck = false
+			isUnstuck = true
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if isUnstuck {
+		err = setActive(p.cache, id, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	return p.resolve()
+}
+
+func (s *state) hasFailedCompensations() bool {
+	for key, op := range s.Failed {
+		if key == op.getKey(true) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,223 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func controlWorkflow() Workflow {
+	return Workflow{
+		Name:  "control workflow",
+		Start: "s1",
+		End:   "s3",
+		Operations: []Operation{
+			{
+				Name: "op1",
+				From: "s1",
+				To:   "s2",
+			},
+			{
+				Name: "op2",
+				From: "s2",
+				To:   "s3",
+			},
+		},
+	}
+}
+
+func TestProcessorCancel(t *testing.T) {
+	w := controlWorkflow()
+	cache := NewCacheMock()
+	producer := NewProducerMock()
+	proc := NewProcessor(cache, producer)
+	assert.NoError(t, proc.StartWorkflow(w, "1"))
+
+	// op1 is in progress so its compensation waits for the result
+	assert.NoError(t, proc.Cancel(w, "1"))
+	status, err := GetWorkflowStatus(cache, "1")
+	assert.NoError(t, err)
+	assert.True(t, status.IsCancelled)
+	assert.True(t, status.IsRollback)
+	assert.Len(t, status.InProgress, 1)
+
+	op1 := w.Operations[0]
+	assert.NoError(t, proc.OnComplete(w, op1.toPayload("1", w, false, "done")))
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_START, op1.toPayload("1", w, true, map[string]interface{}{"input": nil})))
+	assert.False(t, producer.Has(WORKFLOW_OPERATION_START, w.Operations[1].toPayload("1", w, false, map[string]interface{}{"op1": "done"})))
+
+	assert.NoError(t, proc.OnComplete(w, op1.toPayload("1", w, true, nil)))
+	assert.Len(t, producer.messages[WORKFLOW_ROLLBACKED], 1)
+
+	assert.EqualError(t, proc.Cancel(w, "1"), "workflow 1 is completed")
+}
+
+func TestProcessorPause(t *testing.T) {
+	w := controlWorkflow()
+	cache := NewCacheMock()
+	producer := NewProducerMock()
+	proc := NewProcessor(cache, producer)
+	assert.NoError(t, proc.StartWorkflow(w, "1"))
+	assert.NoError(t, proc.Pause(w, "1"))
+
+	// the operation in progress is completed but the next one is not spawned
+	assert.NoError(t, proc.OnComplete(w, w.Operations[0].toPayload("1", w, false, "done")))
+	op2 := w.Operations[1].toPayload("1", w, false, map[string]interface{}{"op1": "done"})
+	assert.False(t, producer.Has(WORKFLOW_OPERATION_START, op2))
+
+	status, err := GetWorkflowStatus(cache, "1")
+	assert.NoError(t, err)
+	assert.True(t, status.IsPaused)
+
+	assert.NoError(t, proc.Continue(w, "1"))
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_START, op2))
+	assert.EqualError(t, proc.Continue(w, "1"), "workflow 1 is not paused")
+
+	assert.NoError(t, proc.OnComplete(w, w.Operations[1].toPayload("1", w, false, nil)))
+	assert.Len(t, producer.messages[WORKFLOW_COMPLETED], 1)
+	assert.EqualError(t, proc.Pause(w, "1"), "workflow 1 is completed")
+}
+
+func TestProcessorForceComplete(t *testing.T) {
+	w := controlWorkflow()
+	cache := NewCacheMock()
+	producer := NewProducerMock()
+	proc := NewProcessor(cache, producer)
+	assert.NoError(t, proc.StartWorkflow(w, "1"))
+
+	assert.EqualError(t, proc.ForceComplete(w, "1", "op3", false, nil), "operation op3 is not found")
+	assert.EqualError(t, proc.ForceComplete(w, "1", "op2", false, nil), "operation op2 is not in progress")
+
+	assert.NoError(t, proc.ForceComplete(w, "1", "op1", false, "manual"))
+	status, err := GetWorkflowStatus(cache, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, []OperationStatus{{Operation: w.Operations[0]}}, status.Settled)
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_START, w.Operations[1].toPayload("1", w, false, map[string]interface{}{"op1": "manual"})))
+
+	// the late result of the completed operation is ignored
+	assert.NoError(t, proc.OnComplete(w, w.Operations[0].toPayload("1", w, false, "late")))
+	status, err = GetWorkflowStatus(cache, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "manual", status.Data["s2"]["op1"])
+}
+
+func TestProcessorSkip(t *testing.T) {
+	w := controlWorkflow()
+	cache := NewCacheMock()
+	producer := NewProducerMock()
+	proc := NewProcessor(cache, producer)
+
+	// the vertex reached by the skipped operation skips the next operations
+	assert.NoError(t, proc.StartWorkflow(w, "1"))
+	assert.NoError(t, proc.Skip(w, "1", "op1", false))
+	status, err := GetWorkflowStatus(cache, "1")
+	assert.NoError(t, err)
+	assert.True(t, status.Completed)
+	assert.False(t, status.IsRollback)
+	assert.Len(t, status.Skipped, 2)
+
+	// the failed compensation of the stuck workflow is skipped
+	assert.NoError(t, proc.StartWorkflow(w, "2"))
+	op1 := w.Operations[0]
+	assert.NoError(t, proc.OnComplete(w, op1.toPayload("2", w, false, "done")))
+	assert.NoError(t, proc.OnFailure(w, w.Operations[1].toPayload("2", w, false, nil)))
+	assert.NoError(t, proc.OnFailure(w, op1.toPayload("2", w, true, nil)))
+
+	status, err = GetWorkflowStatus(cache, "2")
+	assert.NoError(t, err)
+	assert.True(t, status.IsStuck)
+
+	assert.NoError(t, proc.Skip(w, "2", "op1", true))
+	status, err = GetWorkflowStatus(cache, "2")
+	assert.NoError(t, err)
+	assert.False(t, status.IsStuck)
+	assert.True(t, status.Completed)
+	assert.True(t, status.IsRollback)
+
+	ids, err := GetActiveIDs(cache)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, ids)
+}
+
+func TestProcessorConcurrentSpawn(t *testing.T) {
+	w := controlWorkflow()
+	cache := NewCacheMock()
+	producer := NewProducerMock()
+	proc := NewProcessor(cache, producer)
+	assert.NoError(t, proc.StartWorkflow(w, "1"))
+
+	// the resolution of the stale state spawns op1 again
+	stale := NewProcessor(cache, producer).(*processor)
+	stale.workflow = w
+	stale.state = state{
+		ID: "1",
+	}
+	assert.NoError(t, stale.state.load(cache))
+	stale.state.InProgress = map[string]Operation{}
+	assert.NoError(t, stale.spawnOperation(w.Operations[0]))
+	assert.Len(t, producer.messages[WORKFLOW_OPERATION_START], 1)
+
+	assert.NoError(t, proc.OnFailure(w, w.Operations[0].toPayload("1", w, false, nil)))
+	assert.NoError(t, stale.spawnOperation(w.Operations[0]))
+	assert.NoError(t, stale.resolve())
+
+	assert.Len(t, producer.messages[WORKFLOW_OPERATION_START], 1)
+
+	status, err := GetWorkflowStatus(cache, "1")
+	assert.NoError(t, err)
+	assert.Empty(t, status.InProgress)
+	assert.True(t, status.IsRollback)
+}
+
+func TestProcessorCancelChild(t *testing.T) {
+	cache := NewCacheMock()
+	producer := NewProducerMock()
+	proc := NewProcessor(cache, producer)
+	reserveIDs(t, cache, "1")
+
+	payment, err := NewBuilder("payment").
+		Start("p1").
+		Step("charge", "p1", "p2").
+		End("p2").
+		Build()
+	assert.NoError(t, err)
+	_, err = RegisterDefinition(cache, payment)
+	assert.NoError(t, err)
+
+	w, err := NewBuilder("order").
+		Start("s1").
+		Step("pay", "s1", "s2", WithWorkflow("payment", 0)).
+		End("s2").
+		Build()
+	assert.NoError(t, err)
+
+	assert.NoError(t, proc.StartWorkflow(w, "1"))
+	status, err := GetWorkflowStatus(cache, "1")
+	assert.NoError(t, err)
+	childID := status.Children["pay"]
+
+	child, err := LoadWorkflow(cache, childID)
+	assert.NoError(t, err)
+	assert.NoError(t, proc.StartWorkflow(child, childID))
+
+	// the running child workflow is cancelled with its parent
+	assert.NoError(t, proc.Cancel(w, "1"))
+	status, err = GetWorkflowStatus(cache, childID)
+	assert.NoError(t, err)
+	assert.True(t, status.IsCancelled)
+	assert.True(t, status.IsRollback)
+
+	// the charge in progress is compensated when it is completed and the child rollback fails the parent operation
+	charge := child.Operations[0]
+	assert.NoError(t, proc.OnComplete(child, charge.toPayload(childID, child, false, "charged")))
+	assert.NoError(t, proc.OnComplete(child, charge.toPayload(childID, child, true, "refunded")))
+
+	failed := w.Operations[0].toPayload("1", w, false, map[string]interface{}{"charge": "refunded"})
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_FAILED, failed))
+	assert.NoError(t, proc.OnFailure(w, failed))
+
+	status, err = GetWorkflowStatus(cache, "1")
+	assert.NoError(t, err)
+	assert.True(t, status.Completed)
+	assert.True(t, status.IsRollback)
+}
@@ -0,0 +1,180 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	mc "go-micro.dev/v4/cache"
+)
+
+const definitionsKey = "workflow:templates"
+
+var ErrDefinitionNotFound = errors.New("workflow definition is not found")
+
+func getDefinitionKey(name string) string {
+	return fmt.Sprintf("workflow:template:%s", name)
+}
+
+func getVersions(cache Cache, name string) ([]Workflow, error) {
+	ctx := context.Background()
+	versions := []Workflow{}
+
+	raw, err := cache.Get(ctx, getDefinitionKey(name))
+	if err == mc.ErrKeyNotFound {
+		return versions, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal([]byte(raw), &versions)
+	return versions, err
+}
+
+// RegisterDefinition stores the workflow as the next version of the named template
+func RegisterDefinition(cache Cache, w Workflow) (Workflow, error) {
+	w.Payload = nil
+	if w.Name == "" {
+		e := &ValidationError{}
+		e.add(ISSUE_EMPTY_NAME, "", "", "workflow definition has an empty name")
+		return w, e
+	}
+
+	err := Validate(w)
+	if err != nil {
+		return w, err
+	}
+
+	recursion, err := findRecursion(cache, w.Name, w.Operations, []string{w.Name}, make(map[string]bool))
+	if err != nil {
+		return w, err
+	}
+	if len(recursion) > 0 {
+		e := &ValidationError{}
+		e.add(ISSUE_RECURSIVE_WORKFLOW, "", "", "workflow %s starts itself %s", w.Name, strings.Join(recursion, " -> "))
+		return w, e
+	}
+
+	// all versions are kept under the same key so the version is assigned atomically
+	err = updateValue(cache, getDefinitionKey(w.Name), func(raw string, found bool) (interface{}, error) {
+		versions := []Workflow{}
+		if found {
+			err := json.Unmarshal([]byte(raw), &versions)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		w.Version = len(versions) + 1
+		return append(versions, w), nil
+	})
+	if err != nil {
+		return w, err
+	}
+
+	err = updateValue(cache, definitionsKey, func(raw string, found bool) (interface{}, error) {
+		names := make(map[string]bool)
+		if found {
+			err := json.Unmarshal([]byte(raw), &names)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		names[w.Name] = true
+		return names, nil
+	})
+
+	return w, err
+}
+
+// findRecursion returns the path of the registered definitions the operations start the latest version of the named workflow by
+func findRecursion(cache Cache, name string, ops []Operation, path []string, visited map[string]bool) ([]string, error) {
+	for _, op := range ops {
+		if !op.isWorkflow() {
+			continue
+		}
+
+		next := append(append([]string{}, path...), op.Workflow)
+		if op.Workflow == name && op.WorkflowVersion == 0 {
+			return next, nil
+		}
+
+		key := fmt.Sprintf("%s:%d", op.Workflow, op.WorkflowVersion)
+		if visited[key] {
+			continue
+		}
+		visited[key] = true
+
+		child, err := GetDefinition(cache, op.Workflow, op.WorkflowVersion)
+		if err == ErrDefinitionNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		recursion, err := findRecursion(cache, name, child.Operations, next, visited)
+		if err != nil || len(recursion) > 0 {
+			return recursion, err
+		}
+	}
+
+	return nil, nil
+}
+
+// GetDefinition returns the version of the named template, the latest one if the version is 0
+func GetDefinition(cache Cache, name string, version int) (Workflow, error) {
+	versions, err := getVersions(cache, name)
+	if err != nil {
+		return Workflow{}, err
+	}
+
+	if version == 0 {
+		version = len(versions)
+	}
+
+	if version < 1 || version > len(versions) {
+		return Workflow{}, ErrDefinitionNotFound
+	}
+
+	return versions[version-1], nil
+}
+
+// ListDefinitions returns the latest versions of all templates sorted by name
+func ListDefinitions(cache Cache) ([]Workflow, error) {
+	ctx := context.Background()
+	names := make(map[string]bool)
+
+	raw, err := cache.Get(ctx, definitionsKey)
+	if err != nil && err != mc.ErrKeyNotFound {
+		return nil, err
+	}
+
+	if err == nil {
+		err = json.Unmarshal([]byte(raw), &names)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	keys := []string{}
+	for name := range names {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+
+	result := []Workflow{}
+	for _, name := range keys {
+		w, err := GetDefinition(cache, name, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, w)
+	}
+
+	return result, nil
+}
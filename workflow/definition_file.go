@@ -0,0 +1,271 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type DefinitionError struct {
+	Line    int
+	Message string
+}
+
+func (e *DefinitionError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+type duration struct {
+	value time.Duration
+}
+
+func (d *duration) UnmarshalYAML(node *yaml.Node) error {
+	value, err := time.ParseDuration(node.Value)
+	if err != nil || value < 0 {
+		return &DefinitionError{Line: node.Line, Message: fmt.Sprintf("invalid duration %q", node.Value)}
+	}
+
+	d.value = value
+	return nil
+}
+
+type retryFile struct {
+	MaxAttempts    int      `yaml:"maxAttempts"`
+	InitialBackoff duration `yaml:"initialBackoff"`
+	Multiplier     float64  `yaml:"multiplier"`
+	MaxBackoff     duration `yaml:"maxBackoff"`
+}
+
+func (r *retryFile) UnmarshalYAML(node *yaml.Node) error {
+	err := checkFields(node, "maxAttempts", "initialBackoff", "multiplier", "maxBackoff")
+	if err != nil {
+		return err
+	}
+
+	// the alias type prevents the recursive call of UnmarshalYAML
+	type retry retryFile
+	return node.Decode((*retry)(r))
+}
+
+type operationFile struct {
+	line              int
+	Name              string     `yaml:"name"`
+	From              string     `yaml:"from"`
+	To                string     `yaml:"to"`
+	Timeout           duration   `yaml:"timeout"`
+	Retry             *retryFile `yaml:"retry"`
+	CompensationRetry *retryFile `yaml:"compensationRetry"`
+	Condition         string     `yaml:"condition"`
+	ForEach           string     `yaml:"forEach"`
+	Workflow          string     `yaml:"workflow"`
+	WorkflowVersion   int        `yaml:"workflowVersion"`
+	Type              string     `yaml:"type"`
+	Service           string     `yaml:"service"`
+}
+
+func (op *operationFile) UnmarshalYAML(node *yaml.Node) error {
+	err := checkFields(node, "name", "from", "to", "timeout", "retry", "compensationRetry", "condition", "forEach", "workflow", "workflowVersion", "type", "service")
+	if err != nil {
+		return err
+	}
+
+	type operation operationFile
+	err = node.Decode((*operation)(op))
+	if err != nil {
+		return err
+	}
+
+	op.line = node.Line
+	return nil
+}
+
+type definitionFile struct {
+	Name       string            `yaml:"name"`
+	Start      string            `yaml:"start"`
+	End        string            `yaml:"end"`
+	Timeout    duration          `yaml:"timeout"`
+	Metadata   map[string]string `yaml:"metadata"`
+	Operations []operationFile   `yaml:"operations"`
+}
+
+func (r *retryFile) toRetryPolicy() *RetryPolicy {
+	if r == nil {
+		return nil
+	}
+
+	return &RetryPolicy{
+		MaxAttempts:    r.MaxAttempts,
+		InitialBackoff: r.InitialBackoff.value,
+		Multiplier:     r.Multiplier,
+		MaxBackoff:     r.MaxBackoff.value,
+	}
+}
+
+// LoadDefinition parses a YAML or JSON workflow definition and validates it
+func LoadDefinition(r io.Reader) (Workflow, error) {
+	var root yaml.Node
+	err := yaml.NewDecoder(r).Decode(&root)
+	if err == io.EOF {
+		return Workflow{}, &DefinitionError{Line: 1, Message: "workflow definition is empty"}
+	} else if err != nil {
+		return Workflow{}, err
+	}
+
+	document := root.Content[0]
+	err = checkFields(document, "name", "start", "end", "timeout", "metadata", "operations")
+	if err != nil {
+		return Workflow{}, err
+	}
+
+	var file definitionFile
+	err = document.Decode(&file)
+	if err != nil {
+		return Workflow{}, err
+	}
+
+	w := Workflow{
+		Name:       file.Name,
+		Start:      file.Start,
+		End:        file.End,
+		Operations: []Operation{},
+		Timeout:    file.Timeout.value,
+		Metadata:   file.Metadata,
+	}
+
+	lines := make(map[string]int)
+	for _, op := range file.Operations {
+		lines[op.Name] = op.line
+		w.Operations = append(w.Operations, Operation{
+			Name:              op.Name,
+			From:              op.From,
+			To:                op.To,
+			Retry:             op.Retry.toRetryPolicy(),
+			Timeout:           op.Timeout.value,
+			CompensationRetry: op.CompensationRetry.toRetryPolicy(),
+			Condition:         op.Condition,
+			ForEach:           op.ForEach,
+			Workflow:          op.Workflow,
+			WorkflowVersion:   op.WorkflowVersion,
+			Type:              op.Type,
+			Service:           op.Service,
+		})
+	}
+
+	if w.Name == "" {
+		return w, &DefinitionError{Line: document.Line, Message: "workflow definition has an empty name"}
+	}
+
+	err = Validate(w)
+	if verr, ok := err.(*ValidationError); ok {
+		issue := verr.Issues[0]
+		line := lines[issue.Operation]
+		if line == 0 {
+			line = document.Line
+		}
+
+		return w, &DefinitionError{Line: line, Message: verr.Error()}
+	}
+
+	return w, err
+}
+
+// LoadDefinitions registers all definitions of the directory, unchanged definitions are not registered again,
+// the operation types are checked against the executors unless they are nil
+func LoadDefinitions(cache Cache, dir string, executors Executors) ([]Workflow, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		switch filepath.Ext(entry.Name()) {
+		case ".yaml", ".yml", ".json":
+			if !entry.IsDir() {
+				names = append(names, entry.Name())
+			}
+		}
+	}
+	sort.Strings(names)
+
+	result := []Workflow{}
+	for _, name := range names {
+		w, err := loadDefinitionFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		if executors != nil {
+			err = executors.Validate(w)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+		}
+
+		latest, err := GetDefinition(cache, w.Name, 0)
+		if err != nil && err != ErrDefinitionNotFound {
+			return nil, err
+		}
+
+		if err == nil && isSameDefinition(latest, w) {
+			result = append(result, latest)
+			continue
+		}
+
+		w, err = RegisterDefinition(cache, w)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		result = append(result, w)
+	}
+
+	return result, nil
+}
+
+func loadDefinitionFile(path string) (Workflow, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Workflow{}, err
+	}
+	defer f.Close()
+
+	return LoadDefinition(f)
+}
+
+func isSameDefinition(a Workflow, b Workflow) bool {
+	a.Version = 0
+	b.Version = 0
+
+	// definitions are compared in their stored form
+	rawA, errA := json.Marshal(a)
+	rawB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(rawA) == string(rawB)
+}
+
+// checkFields rejects unknown fields of the mapping node
+func checkFields(node *yaml.Node, fields ...string) error {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	known := make(map[string]bool)
+	for _, field := range fields {
+		known[field] = true
+	}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if !known[key.Value] {
+			return &DefinitionError{Line: key.Line, Message: fmt.Sprintf("unknown field %q", key.Value)}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const yamlDefinition = `name: order
+start: s1
+end: s2
+timeout: 1m
+metadata:
+  owner: shop
+operations:
+  - name: op1
+    from: s1
+    to: s2
+    timeout: 5s
+    retry:
+      maxAttempts: 3
+      initialBackoff: 100ms
+      multiplier: 2
+  - name: op2
+    from: s1
+    to: s2
+    compensationRetry:
+      maxAttempts: 2
+`
+
+func TestLoadDefinition(t *testing.T) {
+	expected := Workflow{
+		Name:  "order",
+		Start: "s1",
+		End:   "s2",
+		Operations: []Operation{
+			{
+				Name:    "op1",
+				From:    "s1",
+				To:      "s2",
+				Timeout: 5 * time.Second,
+				Retry: &RetryPolicy{
+					MaxAttempts:    3,
+					InitialBackoff: 100 * time.Millisecond,
+					Multiplier:     2,
+				},
+			},
+			{
+				Name: "op2",
+				From: "s1",
+				To:   "s2",
+				CompensationRetry: &RetryPolicy{
+					MaxAttempts: 2,
+				},
+			},
+		},
+		Timeout:  time.Minute,
+		Metadata: map[string]string{"owner": "shop"},
+	}
+
+	w, err := LoadDefinition(strings.NewReader(yamlDefinition))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, w)
+
+	json := `{
+		"name": "order",
+		"start": "s1",
+		"end": "s2",
+		"timeout": "1m",
+		"metadata": {"owner": "shop"},
+		"operations": [
+			{"name": "op1", "from": "s1", "to": "s2", "timeout": "5s", "retry": {"maxAttempts": 3, "initialBackoff": "100ms", "multiplier": 2}},
+			{"name": "op2", "from": "s1", "to": "s2", "compensationRetry": {"maxAttempts": 2}}
+		]
+	}`
+
+	w, err = LoadDefinition(strings.NewReader(json))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, w)
+}
+
+func TestLoadDefinitionErrors(t *testing.T) {
+	var tests = map[string]struct {
+		definition string
+		expected   string
+	}{
+		"should report unknown field": {
+			definition: "name: order\nstart: s1\nend: s2\nops: []\n",
+			expected:   `line 4: unknown field "ops"`,
+		},
+		"should report unknown operation field": {
+			definition: "name: order\nstart: s1\nend: s2\noperations:\n  - name: op1\n    form: s1\n",
+			expected:   `line 6: unknown field "form"`,
+		},
+		"should report invalid duration": {
+			definition: "name: order\nstart: s1\nend: s2\noperations:\n  - name: op1\n    from: s1\n    to: s2\n    timeout: soon\n",
+			expected:   `line 8: invalid duration "soon"`,
+		},
+		"should report invalid operation": {
+			definition: "name: order\nstart: s1\nend: s2\noperations:\n  - name: op1\n    from: s1\n    to: s2\n  - name: op1\n    from: s1\n    to: s2\n",
+			expected:   "line 8: invalid workflow: operation op1 is duplicated",
+		},
+		"should report syntax error": {
+			definition: "name: order\nstart: s1\n  end: s2\n",
+			expected:   "line 3",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadDefinition(strings.NewReader(tc.definition))
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
+
+func TestLoadDefinitions(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "order.yaml"), []byte(yamlDefinition), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a definition"), 0644))
+
+	cache := NewCacheMock()
+
+	definitions, err := LoadDefinitions(cache, dir, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(definitions))
+	assert.Equal(t, 1, definitions[0].Version)
+
+	// the unchanged definition is not registered again on the next startup
+	definitions, err = LoadDefinitions(cache, dir, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, definitions[0].Version)
+
+	changed := strings.Replace(yamlDefinition, "timeout: 1m", "timeout: 2m", 1)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "order.yaml"), []byte(changed), 0644))
+
+	definitions, err = LoadDefinitions(cache, dir, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, definitions[0].Version)
+
+	// the operation type without an executor is rejected before the definition is registered
+	typed := strings.Replace(changed, "timeout: 2m", "timeout: 3m", 1) + "  - name: notify\n    from: s1\n    to: s2\n    type: grpc\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "order.yaml"), []byte(typed), 0644))
+
+	_, err = LoadDefinitions(cache, dir, Executors{})
+	assert.Error(t, err)
+
+	latest, err := GetDefinition(cache, "order", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, latest.Version)
+}
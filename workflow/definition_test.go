@@ -0,0 +1,103 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefinition(t *testing.T) {
+	cache := NewCacheMock()
+
+	w := Workflow{
+		Name:  "order",
+		Start: "s1",
+		End:   "s2",
+		Operations: []Operation{
+			{Name: "op1", From: "s1", To: "s2"},
+		},
+		Payload: "ignored",
+	}
+
+	v1, err := RegisterDefinition(cache, w)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v1.Version)
+	assert.Nil(t, v1.Payload)
+
+	w.Operations = append(w.Operations, Operation{Name: "op2", From: "s1", To: "s2"})
+	v2, err := RegisterDefinition(cache, w)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v2.Version)
+
+	latest, err := GetDefinition(cache, "order", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, v2, latest)
+
+	pinned, err := GetDefinition(cache, "order", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, v1, pinned)
+
+	_, err = GetDefinition(cache, "order", 3)
+	assert.Equal(t, ErrDefinitionNotFound, err)
+
+	_, err = GetDefinition(cache, "payment", 0)
+	assert.Equal(t, ErrDefinitionNotFound, err)
+
+	_, err = RegisterDefinition(cache, Workflow{Name: "invalid", Start: "s1", End: "s1"})
+	assert.IsType(t, &ValidationError{}, err)
+
+	definitions, err := ListDefinitions(cache)
+	assert.NoError(t, err)
+	assert.Equal(t, []Workflow{v2}, definitions)
+}
+
+func TestDefinitionRecursion(t *testing.T) {
+	cache := NewCacheMock()
+
+	getCodes := func(err error) []string {
+		codes := []string{}
+		if err != nil {
+			for _, issue := range err.(*ValidationError).Issues {
+				codes = append(codes, issue.Code)
+			}
+		}
+		return codes
+	}
+
+	order := Workflow{
+		Name:  "order",
+		Start: "s1",
+		End:   "s2",
+		Operations: []Operation{
+			{Name: "pay", From: "s1", To: "s2", Workflow: "payment"},
+		},
+	}
+	_, err := RegisterDefinition(cache, order)
+	assert.NoError(t, err)
+
+	self := Workflow{
+		Name:  "payment",
+		Start: "p1",
+		End:   "p2",
+		Operations: []Operation{
+			{Name: "charge", From: "p1", To: "p2", Workflow: "payment"},
+		},
+	}
+	_, err = RegisterDefinition(cache, self)
+	assert.Equal(t, []string{ISSUE_RECURSIVE_WORKFLOW}, getCodes(err))
+
+	// the payment started by the order can not start the order again
+	payment := self
+	payment.Operations = []Operation{{Name: "charge", From: "p1", To: "p2", Workflow: "order"}}
+	_, err = RegisterDefinition(cache, payment)
+	assert.Equal(t, []string{ISSUE_RECURSIVE_WORKFLOW}, getCodes(err))
+
+	// the pinned version of the order does not start the payment
+	order.Operations = []Operation{{Name: "pay", From: "s1", To: "s2"}}
+	_, err = RegisterDefinition(cache, order)
+	assert.NoError(t, err)
+
+	payment.Operations = []Operation{{Name: "charge", From: "p1", To: "p2", Workflow: "order", WorkflowVersion: 2}}
+	_, err = RegisterDefinition(cache, payment)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,316 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+const ENGINE_INDEX_KEY = "workflow:engine:index"
+
+// ENGINE_ID_PREFIX keeps the engine workflow ids apart from the ids reserved by the coordinator in the shared cache
+const ENGINE_ID_PREFIX = "engine-"
+
+// ENGINE_TIMER_INTERVAL is used if the timer interval of the engine is not set
+const ENGINE_TIMER_INTERVAL = time.Second
+
+type message struct {
+	topic string
+	body  []byte
+}
+
+// bus is the in-memory producer of the engine, messages are queued until the engine loop takes them
+type bus struct {
+	mu       sync.Mutex
+	messages []message
+	err      error
+	ready    chan struct{}
+}
+
+func newBus() *bus {
+	return &bus{
+		ready: make(chan struct{}, 1),
+	}
+}
+
+func (b *bus) Init() error {
+	return nil
+}
+
+func (b *bus) Connect() error {
+	return nil
+}
+
+func (b *bus) SendMessage(topic string, m interface{}) error {
+	body, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.messages = append(b.messages, message{topic: topic, body: body})
+	b.mu.Unlock()
+
+	b.signal()
+	return nil
+}
+
+// abort stops the engine loop with the error of the step goroutine
+func (b *bus) abort(err error) {
+	b.mu.Lock()
+	if b.err == nil {
+		b.err = err
+	}
+	b.mu.Unlock()
+
+	b.signal()
+}
+
+func (b *bus) signal() {
+	select {
+	case b.ready <- struct{}{}:
+	default:
+	}
+}
+
+func (b *bus) drain() ([]message, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	result := b.messages
+	b.messages = nil
+	return result, b.err
+}
+
+// StepFunc gets the data of the source vertex of the operation and returns the operation result
+type StepFunc func(ctx context.Context, input interface{}) (interface{}, error)
+
+// Engine runs workflows inside the process, the operations are executed by the registered step functions
+type Engine struct {
+	cache Cache
+	funcs *FuncExecutor
+	// executors are replaced on registration so the running steps read them without the lock
+	mu        sync.RWMutex
+	executors Executors
+	// TimerInterval is the period of operation and workflow timeouts checks
+	TimerInterval time.Duration
+}
+
+func NewEngine(cache Cache) *Engine {
+	if cache == nil {
+		cache = NewCache()
+	}
+
+	funcs := NewFuncExecutor()
+
+	return &Engine{
+		cache: cache,
+		funcs: funcs,
+		executors: Executors{
+			EXECUTOR_FUNC: funcs,
+			EXECUTOR_HTTP: NewHTTPExecutor(nil),
+		},
+		TimerInterval: ENGINE_TIMER_INTERVAL,
+	}
+}
+
+// Register adds the step run by the operation of the name and its compensation, the compensation can be nil
+func (e *Engine) Register(name string, step StepFunc, compensation StepFunc) {
+	e.funcs.Register(name, func(ctx context.Context, op OperationPayload) (ExecutionResult, error) {
+		fn := step
+		if op.IsRollback {
+			fn = compensation
+		}
+
+		if fn == nil {
+			return ExecutionResult{}, nil
+		}
+
+		result, err := fn(ctx, op.Payload)
+		if err != nil {
+			return ExecutionResult{IsFailed: true, Payload: err.Error()}, nil
+		}
+
+		return ExecutionResult{Payload: result}, nil
+	})
+}
+
+// RegisterExecutor adds the executor of the operation type, operations without a type run the registered steps
+func (e *Engine) RegisterExecutor(name string, executor Executor) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	executors := make(Executors, len(e.executors)+1)
+	for key, value := range e.executors {
+		executors[key] = value
+	}
+	executors[name] = executor
+	e.executors = executors
+}
+
+func (e *Engine) getExecutors() Executors {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.executors
+}
+
+// Run starts the workflow with the payload and waits until it is completed, rollbacked or stuck
+func (e *Engine) Run(ctx context.Context, w Workflow, payload interface{}) (WorkflowPayload, error) {
+	err := Validate(w)
+	if err != nil {
+		return WorkflowPayload{}, err
+	}
+
+	err = e.getExecutors().Validate(w)
+	if err != nil {
+		return WorkflowPayload{}, err
+	}
+
+	index, err := ReserveID(ENGINE_INDEX_KEY, e.cache)
+	if err != nil {
+		return WorkflowPayload{}, err
+	}
+	id := ENGINE_ID_PREFIX + index
+
+	w.Payload = payload
+	err = SaveWorkflow(e.cache, id, w)
+	if err != nil {
+		return WorkflowPayload{}, err
+	}
+
+	b := newBus()
+	err = NewProcessor(e.cache, b).StartWorkflow(w, id)
+	if err != nil {
+		return WorkflowPayload{}, err
+	}
+
+	ids := []string{id}
+
+	interval := e.TimerInterval
+	if interval <= 0 {
+		interval = ENGINE_TIMER_INTERVAL
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return WorkflowPayload{}, ctx.Err()
+		case now := <-ticker.C:
+			for _, id := range ids {
+				err := e.checkTimeout(b, id, now)
+				if err != nil {
+					return WorkflowPayload{}, err
+				}
+			}
+		case <-b.ready:
+			messages, err := b.drain()
+			if err != nil {
+				return WorkflowPayload{}, err
+			}
+
+			for _, m := range messages {
+				result, done, err := e.handle(ctx, b, m, &ids)
+				if err != nil || (done && result.ID == id) {
+					return result, err
+				}
+			}
+		}
+	}
+}
+
+func (e *Engine) checkTimeout(b *bus, id string, now time.Time) error {
+	w, err := LoadWorkflow(e.cache, id)
+	if err != nil {
+		return err
+	}
+
+	return NewProcessor(e.cache, b).OnTimeout(w, id, now)
+}
+
+// handle dispatches the message the same way the coordinator subscribers do
+func (e *Engine) handle(ctx context.Context, b *bus, m message, ids *[]string) (WorkflowPayload, bool, error) {
+	proc := NewProcessor(e.cache, b)
+
+	switch m.topic {
+	case WORKFLOW_OPERATION_START:
+		var op OperationPayload
+		err := json.Unmarshal(m.body, &op)
+		if err != nil {
+			return WorkflowPayload{}, false, err
+		}
+
+		go e.execute(ctx, b, op)
+		return WorkflowPayload{}, false, nil
+	case WORKFLOW_OPERATION_COMPLETED, WORKFLOW_OPERATION_FAILED:
+		var op OperationPayload
+		err := json.Unmarshal(m.body, &op)
+		if err != nil {
+			return WorkflowPayload{}, false, err
+		}
+
+		w, err := LoadWorkflow(e.cache, op.ID)
+		if err != nil {
+			return WorkflowPayload{}, false, err
+		}
+
+		if m.topic == WORKFLOW_OPERATION_COMPLETED {
+			return WorkflowPayload{}, false, proc.OnComplete(w, op)
+		}
+		return WorkflowPayload{}, false, proc.OnFailure(w, op)
+	case WORKFLOW_START:
+		var payload WorkflowPayload
+		err := json.Unmarshal(m.body, &payload)
+		if err != nil {
+			return WorkflowPayload{}, false, err
+		}
+
+		child, err := LoadWorkflow(e.cache, payload.ID)
+		if err != nil {
+			return WorkflowPayload{}, false, err
+		}
+
+		if payload.IsRollback {
+			return WorkflowPayload{}, false, proc.Compensate(child, payload.ID)
+		}
+
+		*ids = append(*ids, payload.ID)
+		return WorkflowPayload{}, false, proc.StartWorkflow(child, payload.ID)
+	default:
+		var payload WorkflowPayload
+		err := json.Unmarshal(m.body, &payload)
+		return payload, err == nil, err
+	}
+}
+
+func (e *Engine) execute(ctx context.Context, b *bus, op OperationPayload) {
+	var result ExecutionResult
+	var err error
+	if op.Operation.Type == "" {
+		ctx, cancel := withTimeout(ctx, op)
+		result, err = e.funcs.Execute(ctx, op)
+		cancel()
+	} else {
+		result, err = e.getExecutors().Execute(ctx, op)
+	}
+
+	topic := WORKFLOW_OPERATION_COMPLETED
+	if err != nil {
+		op.Payload = err.Error()
+		topic = WORKFLOW_OPERATION_FAILED
+	} else {
+		op.Payload = result.Payload
+		if result.IsFailed {
+			topic = WORKFLOW_OPERATION_FAILED
+		}
+	}
+
+	err = b.SendMessage(topic, op)
+	if err != nil {
+		b.abort(err)
+	}
+}
@@ -0,0 +1,172 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type journal struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (j *journal) step(entry string, result interface{}, err error) StepFunc {
+	return func(ctx context.Context, input interface{}) (interface{}, error) {
+		j.mu.Lock()
+		defer j.mu.Unlock()
+
+		j.entries = append(j.entries, entry)
+		return result, err
+	}
+}
+
+func (j *journal) get() []string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return append([]string{}, j.entries...)
+}
+
+func orderWorkflow(t *testing.T) Workflow {
+	w, err := NewBuilder("order").
+		Start("s1").
+		Step("reserve", "s1", "s2").
+		Step("charge", "s2", "s3").
+		End("s3").
+		Build()
+	assert.NoError(t, err)
+
+	return w
+}
+
+func TestEngine(t *testing.T) {
+	j := &journal{}
+	e := NewEngine(nil)
+	e.Register("reserve", j.step("reserve", "reserved", nil), j.step("release", nil, nil))
+	e.Register("charge", func(ctx context.Context, input interface{}) (interface{}, error) {
+		return input, nil
+	}, nil)
+
+	res, err := e.Run(context.Background(), orderWorkflow(t), "order1")
+	assert.NoError(t, err)
+	assert.Equal(t, "engine-1", res.ID)
+	assert.False(t, res.IsRollback)
+	assert.Equal(t, "reserved", res.Data["s2"]["reserve"])
+	assert.Equal(t, map[string]interface{}{"reserve": "reserved"}, res.Data["s3"]["charge"])
+	assert.Equal(t, []string{"reserve"}, j.get())
+}
+
+func TestEngineCompensation(t *testing.T) {
+	j := &journal{}
+	e := NewEngine(nil)
+	e.Register("reserve", j.step("reserve", "reserved", nil), j.step("release", nil, nil))
+	e.Register("charge", j.step("charge", nil, errors.New("card declined")), j.step("refund", nil, nil))
+
+	res, err := e.Run(context.Background(), orderWorkflow(t), "order1")
+	assert.NoError(t, err)
+	assert.True(t, res.IsRollback)
+	assert.Equal(t, []string{"reserve", "charge", "release"}, j.get())
+
+	status, err := GetWorkflowStatus(e.cache, res.ID)
+	assert.NoError(t, err)
+	assert.True(t, status.IsRollback)
+}
+
+func TestEngineRetry(t *testing.T) {
+	attempts := 0
+	e := NewEngine(nil)
+	e.Register("reserve", func(ctx context.Context, input interface{}) (interface{}, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, errors.New("unavailable")
+		}
+		return "reserved", nil
+	}, nil)
+
+	w, err := NewBuilder("order").
+		Start("s1").
+		Step("reserve", "s1", "s2", WithRetry(RetryPolicy{MaxAttempts: 3})).
+		End("s2").
+		Build()
+	assert.NoError(t, err)
+
+	res, err := e.Run(context.Background(), w, nil)
+	assert.NoError(t, err)
+	assert.False(t, res.IsRollback)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestEngineSubWorkflow(t *testing.T) {
+	j := &journal{}
+	e := NewEngine(nil)
+	e.Register("reserve", j.step("reserve", "reserved", nil), nil)
+	e.Register("charge", j.step("charge", "charged", nil), nil)
+	e.Register("ship", j.step("ship", "shipped", nil), nil)
+
+	_, err := RegisterDefinition(e.cache, orderWorkflow(t))
+	assert.NoError(t, err)
+
+	w, err := NewBuilder("delivery").
+		Start("s1").
+		Step("order", "s1", "s2", WithWorkflow("order", 0)).
+		Step("ship", "s2", "s3").
+		End("s3").
+		Build()
+	assert.NoError(t, err)
+
+	res, err := e.Run(context.Background(), w, nil)
+	assert.NoError(t, err)
+	assert.False(t, res.IsRollback)
+	assert.Equal(t, map[string]interface{}{"charge": "charged"}, res.Data["s2"]["order"])
+	assert.Equal(t, []string{"reserve", "charge", "ship"}, j.get())
+}
+
+func TestEngineCancel(t *testing.T) {
+	e := NewEngine(nil)
+	e.Register("reserve", func(ctx context.Context, input interface{}) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := e.Run(ctx, orderWorkflow(t), nil)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestEngineSharedCache(t *testing.T) {
+	cache := NewCache()
+	coordinator := Workflow{Name: "coordinator", Start: "s1", End: "s2", Operations: []Operation{{Name: "op1", From: "s1", To: "s2"}}}
+	err := SaveWorkflow(cache, "1", coordinator)
+	assert.NoError(t, err)
+	err = NewProcessor(cache, NewProducerMock()).StartWorkflow(coordinator, "1")
+	assert.NoError(t, err)
+
+	e := NewEngine(cache)
+	e.TimerInterval = 0
+	e.Register("reserve", nil, nil)
+	e.Register("charge", nil, nil)
+
+	// the engine workflow does not overwrite the state of the coordinator workflow with the same index
+	res, err := e.Run(context.Background(), orderWorkflow(t), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "engine-1", res.ID)
+
+	status, err := GetWorkflowStatus(cache, "1")
+	assert.NoError(t, err)
+	assert.False(t, status.Completed)
+	assert.Equal(t, "op1", status.InProgress[0].Operation.Name)
+}
+
+func TestEngineValidation(t *testing.T) {
+	e := NewEngine(nil)
+
+	_, err := e.Run(context.Background(), Workflow{Name: "order", Start: "s1", End: "s1"}, nil)
+	assert.IsType(t, &ValidationError{}, err)
+}
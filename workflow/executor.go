@@ -0,0 +1,222 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	po "github.com/awe76/sagaproc/proto"
+	client "go-micro.dev/v4/client"
+)
+
+const (
+	EXECUTOR_RPC  = "rpc"
+	EXECUTOR_HTTP = "http"
+	EXECUTOR_FUNC = "func"
+)
+
+// HTTP_TIMEOUT limits the requests of the http executor created without a client
+const HTTP_TIMEOUT = 30 * time.Second
+
+// DEFAULT_SERVICE is called by the rpc executor if the operation has no service
+const DEFAULT_SERVICE = "sagaproc"
+
+type ExecutionResult struct {
+	IsFailed bool        `json:"isFailed"`
+	Payload  interface{} `json:"payload"`
+}
+
+// Executor runs the operation or its compensation, an error is reported as the operation failure
+type Executor interface {
+	Execute(ctx context.Context, op OperationPayload) (ExecutionResult, error)
+}
+
+// withTimeout cancels the execution when the operation times out, compensations are not limited by the timeout
+func withTimeout(ctx context.Context, op OperationPayload) (context.Context, context.CancelFunc) {
+	if op.IsRollback || op.Operation.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, op.Operation.Timeout)
+}
+
+// Executors selects the executor by the operation type, the rpc executor is used if the type is empty
+type Executors map[string]Executor
+
+// Validate rejects the workflow with the operations of the types which have no executor
+func (e Executors) Validate(w Workflow) error {
+	v := &ValidationError{}
+	for _, op := range w.Operations {
+		if _, found := e[op.Type]; op.Type != "" && !op.isWorkflow() && !found {
+			v.add(ISSUE_UNKNOWN_TYPE, op.Name, "", "operation %s has an unknown type %s", op.Name, op.Type)
+		}
+	}
+
+	if len(v.Issues) > 0 {
+		return v
+	}
+
+	return nil
+}
+
+func (e Executors) Execute(ctx context.Context, op OperationPayload) (ExecutionResult, error) {
+	name := op.Operation.Type
+	if name == "" {
+		name = EXECUTOR_RPC
+	}
+
+	executor, found := e[name]
+	if !found {
+		return ExecutionResult{}, fmt.Errorf("unknown operation type: %s", name)
+	}
+
+	ctx, cancel := withTimeout(ctx, op)
+	defer cancel()
+
+	return executor.Execute(ctx, op)
+}
+
+type rpcExecutor struct {
+	client client.Client
+}
+
+// NewRPCExecutor calls the HandleOperation endpoint of the sagaproc contract on the operation service
+func NewRPCExecutor(c client.Client) Executor {
+	return &rpcExecutor{client: c}
+}
+
+// sagaproc identifies workflows by numeric ids, the operations of uuid based workflows are failed
+func toSagaprocID(id string) (int64, error) {
+	result, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("workflow id %s is not numeric, sagaproc operations require numeric workflow ids", id)
+	}
+
+	return result, nil
+}
+
+func (e *rpcExecutor) Execute(ctx context.Context, op OperationPayload) (ExecutionResult, error) {
+	service := op.Operation.Service
+	if service == "" {
+		service = DEFAULT_SERVICE
+	}
+
+	id, err := toSagaprocID(op.ID)
+	if err != nil {
+		return ExecutionResult{}, err
+	}
+
+	req := po.OperationPayload{
+		Id:         id,
+		IsRollback: op.IsRollback,
+		Name:       op.Name,
+		Operation: &po.Operation{
+			From: op.Operation.From,
+			To:   op.Operation.To,
+			Name: op.Operation.Name,
+		},
+	}
+
+	resp, err := po.NewSagaprocService(service, e.client).HandleOperation(ctx, &req)
+	if err != nil {
+		return ExecutionResult{}, err
+	}
+
+	return ExecutionResult{
+		IsFailed: resp.IsFailed,
+		Payload:  resp.Payload,
+	}, nil
+}
+
+type httpExecutor struct {
+	client *http.Client
+}
+
+// NewHTTPExecutor posts the operation payload as json to the operation service url
+// and expects the json execution result in the response, the requests are limited by HTTP_TIMEOUT if the client is nil
+func NewHTTPExecutor(c *http.Client) Executor {
+	if c == nil {
+		c = &http.Client{Timeout: HTTP_TIMEOUT}
+	}
+
+	return &httpExecutor{client: c}
+}
+
+func (e *httpExecutor) Execute(ctx context.Context, op OperationPayload) (ExecutionResult, error) {
+	var result ExecutionResult
+
+	body, err := json.Marshal(op)
+	if err != nil {
+		return result, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, op.Operation.Service, bytes.NewReader(body))
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return result, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return result, fmt.Errorf("%s responded with %s: %s", op.Operation.Service, resp.Status, raw)
+	}
+
+	err = json.Unmarshal(raw, &result)
+	return result, err
+}
+
+type OperationFunc func(ctx context.Context, op OperationPayload) (ExecutionResult, error)
+
+// FuncExecutor runs in-process functions registered by the operation service or name
+type FuncExecutor struct {
+	sync.RWMutex
+	funcs map[string]OperationFunc
+}
+
+func NewFuncExecutor() *FuncExecutor {
+	return &FuncExecutor{
+		funcs: make(map[string]OperationFunc),
+	}
+}
+
+func (e *FuncExecutor) Register(name string, fn OperationFunc) {
+	e.Lock()
+	defer e.Unlock()
+
+	e.funcs[name] = fn
+}
+
+func (e *FuncExecutor) Execute(ctx context.Context, op OperationPayload) (ExecutionResult, error) {
+	name := op.Operation.Service
+	if name == "" && op.Operation.isItem() {
+		name = op.Operation.Parent
+	} else if name == "" {
+		name = op.Operation.Name
+	}
+
+	e.RLock()
+	fn, found := e.funcs[name]
+	e.RUnlock()
+
+	if !found {
+		return ExecutionResult{}, fmt.Errorf("unknown operation func: %s", name)
+	}
+
+	return fn(ctx, op)
+}
@@ -0,0 +1,202 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	po "github.com/awe76/sagaproc/proto"
+	"github.com/stretchr/testify/assert"
+	"go-micro.dev/v4/client"
+)
+
+func TestHTTPExecutor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var op OperationPayload
+		err := json.NewDecoder(r.Body).Decode(&op)
+		assert.NoError(t, err)
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		if op.IsRollback {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		json.NewEncoder(w).Encode(ExecutionResult{
+			IsFailed: op.Payload == "fail",
+			Payload:  op.Operation.Name,
+		})
+	}))
+	defer server.Close()
+
+	op := Operation{Name: "charge", From: "s1", To: "s2", Type: EXECUTOR_HTTP, Service: server.URL}
+	w := Workflow{Name: "order"}
+	e := NewHTTPExecutor(server.Client())
+
+	res, err := e.Execute(context.Background(), op.toPayload("1", w, false, "ok"))
+	assert.NoError(t, err)
+	assert.Equal(t, ExecutionResult{Payload: "charge"}, res)
+
+	res, err = e.Execute(context.Background(), op.toPayload("1", w, false, "fail"))
+	assert.NoError(t, err)
+	assert.True(t, res.IsFailed)
+
+	_, err = e.Execute(context.Background(), op.toPayload("1", w, true, nil))
+	assert.Error(t, err)
+}
+
+func TestFuncExecutor(t *testing.T) {
+	e := NewFuncExecutor()
+	e.Register("charge", func(ctx context.Context, op OperationPayload) (ExecutionResult, error) {
+		return ExecutionResult{Payload: op.Payload}, nil
+	})
+	e.Register("payments", func(ctx context.Context, op OperationPayload) (ExecutionResult, error) {
+		return ExecutionResult{IsFailed: true}, nil
+	})
+
+	executors := Executors{EXECUTOR_FUNC: e}
+	w := Workflow{Name: "order"}
+
+	charge := Operation{Name: "charge", From: "s1", To: "s2", Type: EXECUTOR_FUNC}
+	res, err := executors.Execute(context.Background(), charge.toPayload("1", w, false, 42))
+	assert.NoError(t, err)
+	assert.Equal(t, ExecutionResult{Payload: 42}, res)
+
+	charge.Service = "payments"
+	res, err = executors.Execute(context.Background(), charge.toPayload("1", w, false, 42))
+	assert.NoError(t, err)
+	assert.True(t, res.IsFailed)
+
+	charges := Operation{Name: "charge", From: "s1", To: "s2", Type: EXECUTOR_FUNC, ForEach: "s0.start.items"}
+	item := charges.getItem(1)
+	res, err = executors.Execute(context.Background(), item.toPayload("1", w, false, 7))
+	assert.NoError(t, err)
+	assert.Equal(t, ExecutionResult{Payload: 7}, res)
+
+	unknown := Operation{Name: "refund", From: "s1", To: "s2", Type: EXECUTOR_FUNC}
+	_, err = executors.Execute(context.Background(), unknown.toPayload("1", w, false, nil))
+	assert.Error(t, err)
+
+	// the rpc executor is used by default and it is not registered here
+	charge.Type = ""
+	_, err = executors.Execute(context.Background(), charge.toPayload("1", w, false, nil))
+	assert.EqualError(t, err, "unknown operation type: rpc")
+}
+
+func TestRPCExecutorUUID(t *testing.T) {
+	op := Operation{Name: "charge", From: "s1", To: "s2"}
+	w := Workflow{Name: "order"}
+
+	// sagaproc can not identify the uuid based workflow so its operation is failed before the call
+	_, err := NewRPCExecutor(nil).Execute(context.Background(), op.toPayload("0b6c4a3e-1a2b-4c5d-8e9f-123456789abc", w, false, nil))
+	assert.Error(t, err)
+}
+
+type requestMock struct {
+	client.Request
+	body interface{}
+}
+
+func (r *requestMock) Body() interface{} {
+	return r.body
+}
+
+// clientMock answers the sagaproc call and keeps the sent payloads
+type clientMock struct {
+	client.Client
+	payloads []*po.OperationPayload
+}
+
+func (c *clientMock) NewRequest(service, endpoint string, req interface{}, reqOpts ...client.RequestOption) client.Request {
+	return &requestMock{body: req}
+}
+
+func (c *clientMock) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	c.payloads = append(c.payloads, req.Body().(*po.OperationPayload))
+	rsp.(*po.OperationResponse).Payload = "charged"
+	return nil
+}
+
+func TestRPCExecutorChild(t *testing.T) {
+	cache := NewCacheMock()
+	producer := NewProducerMock()
+	proc := NewProcessor(cache, producer)
+	reserveIDs(t, cache, "1")
+
+	payment, err := NewBuilder("payment").
+		Start("p1").
+		Step("charge", "p1", "p2").
+		End("p2").
+		Build()
+	assert.NoError(t, err)
+	_, err = RegisterDefinition(cache, payment)
+	assert.NoError(t, err)
+
+	w, err := NewBuilder("order").
+		Start("s1").
+		Step("pay", "s1", "s2", WithWorkflow("payment", 0)).
+		End("s2").
+		Build()
+	assert.NoError(t, err)
+
+	assert.NoError(t, proc.StartWorkflow(w, "1"))
+	status, err := GetWorkflowStatus(cache, "1")
+	assert.NoError(t, err)
+	childID := status.Children["pay"]
+
+	child, err := LoadWorkflow(cache, childID)
+	assert.NoError(t, err)
+	assert.NoError(t, proc.StartWorkflow(child, childID))
+
+	// the operation of the child is sent to sagaproc with the numeric id of the child
+	c := &clientMock{}
+	result, err := NewRPCExecutor(c).Execute(context.Background(), child.Operations[0].toPayload(childID, child, false, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, ExecutionResult{Payload: "charged"}, result)
+	assert.Len(t, c.payloads, 1)
+	assert.Equal(t, int64(2), c.payloads[0].Id)
+	assert.Equal(t, "payment", c.payloads[0].Name)
+	assert.Equal(t, "charge", c.payloads[0].Operation.Name)
+}
+
+func TestExecutorTimeout(t *testing.T) {
+	e := NewFuncExecutor()
+	e.Register("charge", func(ctx context.Context, op OperationPayload) (ExecutionResult, error) {
+		<-ctx.Done()
+		return ExecutionResult{}, ctx.Err()
+	})
+
+	executors := Executors{EXECUTOR_FUNC: e}
+	w := Workflow{Name: "order"}
+	op := Operation{Name: "charge", From: "s1", To: "s2", Type: EXECUTOR_FUNC, Timeout: 10 * time.Millisecond}
+
+	// the hung execution is cancelled by the operation timeout
+	_, err := executors.Execute(context.Background(), op.toPayload("1", w, false, nil))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestExecutorsValidate(t *testing.T) {
+	executors := Executors{EXECUTOR_RPC: NewRPCExecutor(nil), EXECUTOR_HTTP: NewHTTPExecutor(nil)}
+	w := Workflow{
+		Name:  "order",
+		Start: "s1",
+		End:   "s2",
+		Operations: []Operation{
+			{Name: "reserve", From: "s1", To: "s2"},
+			{Name: "charge", From: "s1", To: "s2", Type: EXECUTOR_FUNC},
+			{Name: "notify", From: "s1", To: "s2", Type: EXECUTOR_HTTP, Service: "http://localhost/notify"},
+		},
+	}
+
+	// the func executor is not configured so its operation is rejected before the workflow is started
+	err := executors.Validate(w)
+	assert.IsType(t, &ValidationError{}, err)
+	assert.Equal(t, ISSUE_UNKNOWN_TYPE, err.(*ValidationError).Issues[0].Code)
+	assert.Equal(t, "charge", err.(*ValidationError).Issues[0].Operation)
+
+	executors[EXECUTOR_FUNC] = NewFuncExecutor()
+	assert.NoError(t, executors.Validate(w))
+}
@@ -0,0 +1,159 @@
+package workflow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	FORMAT_DOT     = "dot"
+	FORMAT_MERMAID = "mermaid"
+)
+
+const (
+	OPERATION_PENDING      = "pending"
+	OPERATION_IN_PROGRESS  = "in progress"
+	OPERATION_DONE         = "done"
+	OPERATION_COMPENSATING = "compensating"
+	OPERATION_COMPENSATED  = "compensated"
+	OPERATION_FAILED       = "failed"
+	OPERATION_SKIPPED      = "skipped"
+)
+
+var operationColors = map[string]string{
+	OPERATION_PENDING:      "black",
+	OPERATION_IN_PROGRESS:  "orange",
+	OPERATION_DONE:         "green",
+	OPERATION_COMPENSATING: "purple",
+	OPERATION_COMPENSATED:  "blue",
+	OPERATION_FAILED:       "red",
+	OPERATION_SKIPPED:      "gray",
+}
+
+func hasStatus(ops []OperationStatus, op Operation, isRollback bool) bool {
+	for _, s := range ops {
+		if s.IsRollback == isRollback && s.Operation.getKey(false) == op.getKey(false) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getOperationState returns the most recent state of the operation in the workflow status
+func getOperationState(op Operation, status *WorkflowStatus) string {
+	switch {
+	case status == nil:
+		return OPERATION_PENDING
+	case hasStatus(status.Failed, op, true) || hasStatus(status.Failed, op, false):
+		return OPERATION_FAILED
+	case hasStatus(status.Done, op, true):
+		return OPERATION_COMPENSATED
+	case hasStatus(status.InProgress, op, true):
+		return OPERATION_COMPENSATING
+	case hasStatus(status.Done, op, false):
+		return OPERATION_DONE
+	case hasStatus(status.InProgress, op, false):
+		return OPERATION_IN_PROGRESS
+	case hasStatus(status.Skipped, op, false):
+		return OPERATION_SKIPPED
+	default:
+		return OPERATION_PENDING
+	}
+}
+
+// Export renders the workflow graph, the operations are coloured by their states if the status is passed
+func Export(w Workflow, format string, status *WorkflowStatus) (string, error) {
+	switch format {
+	case FORMAT_DOT:
+		return exportDot(w, status), nil
+	case FORMAT_MERMAID:
+		return exportMermaid(w, status), nil
+	default:
+		return "", fmt.Errorf("unknown export format: %s", format)
+	}
+}
+
+func ExportWorkflow(cache Cache, id string, format string) (string, error) {
+	w, err := LoadWorkflow(cache, id)
+	if err != nil {
+		return "", err
+	}
+
+	status, err := GetWorkflowStatus(cache, id)
+	if err != nil {
+		return "", err
+	}
+
+	return Export(w, format, &status)
+}
+
+func getLabel(op Operation, status *WorkflowStatus) string {
+	if status == nil {
+		return op.Name
+	}
+
+	return fmt.Sprintf("%s (%s)", op.Name, getOperationState(op, status))
+}
+
+func exportDot(w Workflow, status *WorkflowStatus) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "digraph %s {\n", strconv.Quote(w.Name))
+	b.WriteString("\trankdir=LR;\n")
+
+	for _, vertex := range getVertices(w) {
+		shape := "circle"
+		if vertex == w.Start || vertex == w.End {
+			shape = "doublecircle"
+		}
+
+		fmt.Fprintf(&b, "\t%s [shape=%s];\n", strconv.Quote(vertex), shape)
+	}
+
+	for _, op := range w.Operations {
+		color := operationColors[getOperationState(op, status)]
+		fmt.Fprintf(&b, "\t%s -> %s [label=%s, color=%s, fontcolor=%s];\n",
+			strconv.Quote(op.From), strconv.Quote(op.To), strconv.Quote(getLabel(op, status)), color, color)
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func escapeMermaid(s string) string {
+	return strings.ReplaceAll(s, "\"", "#quot;")
+}
+
+func exportMermaid(w Workflow, status *WorkflowStatus) string {
+	var b strings.Builder
+
+	b.WriteString("flowchart LR\n")
+
+	// mermaid ids can not contain arbitrary characters so the vertices are numbered
+	ids := make(map[string]string)
+	for i, vertex := range getVertices(w) {
+		ids[vertex] = fmt.Sprintf("v%d", i)
+
+		format := "\t%s((\"%s\"))\n"
+		if vertex == w.Start || vertex == w.End {
+			format = "\t%s(((\"%s\")))\n"
+		}
+
+		fmt.Fprintf(&b, format, ids[vertex], escapeMermaid(vertex))
+	}
+
+	for _, op := range w.Operations {
+		fmt.Fprintf(&b, "\t%s -->|\"%s\"| %s\n", ids[op.From], escapeMermaid(getLabel(op, status)), ids[op.To])
+	}
+
+	if status != nil {
+		for i, op := range w.Operations {
+			color := operationColors[getOperationState(op, status)]
+			fmt.Fprintf(&b, "\tlinkStyle %d stroke:%s,color:%s\n", i, color, color)
+		}
+	}
+
+	return b.String()
+}
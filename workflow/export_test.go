@@ -0,0 +1,63 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExport(t *testing.T) {
+	w, err := NewBuilder("order").
+		Start("s1").
+		Step("op1", "s1", "s2").
+		Step("op2", "s1", "s3").
+		Step("op3", "s3", "s2").
+		End("s2").
+		Build()
+	assert.NoError(t, err)
+
+	dot, err := Export(w, FORMAT_DOT, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `digraph "order" {
+	rankdir=LR;
+	"s1" [shape=doublecircle];
+	"s2" [shape=doublecircle];
+	"s3" [shape=circle];
+	"s1" -> "s2" [label="op1", color=black, fontcolor=black];
+	"s1" -> "s3" [label="op2", color=black, fontcolor=black];
+	"s3" -> "s2" [label="op3", color=black, fontcolor=black];
+}
+`, dot)
+
+	status := WorkflowStatus{
+		IsRollback: true,
+		Done: []OperationStatus{
+			{Operation: w.Operations[0], IsRollback: false},
+			{Operation: w.Operations[0], IsRollback: true},
+			{Operation: w.Operations[1], IsRollback: false},
+		},
+		InProgress: []OperationStatus{
+			{Operation: w.Operations[1], IsRollback: true},
+		},
+		Failed: []OperationStatus{
+			{Operation: w.Operations[2], IsRollback: false},
+		},
+	}
+
+	mermaid, err := Export(w, FORMAT_MERMAID, &status)
+	assert.NoError(t, err)
+	assert.Equal(t, `flowchart LR
+	v0((("s1")))
+	v1((("s2")))
+	v2(("s3"))
+	v0 -->|"op1 (compensated)"| v1
+	v0 -->|"op2 (compensating)"| v2
+	v2 -->|"op3 (failed)"| v1
+	linkStyle 0 stroke:blue,color:blue
+	linkStyle 1 stroke:purple,color:purple
+	linkStyle 2 stroke:red,color:red
+`, mermaid)
+
+	_, err = Export(w, "svg", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,151 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+
+	mc "go-micro.dev/v4/cache"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	fileCacheBucket        = []byte("workflow")
+	fileCacheVersionBucket = []byte("version")
+)
+
+type fileCache struct {
+	db *bolt.DB
+}
+
+func NewFileCache(path string) (Cache, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{fileCacheBucket, fileCacheVersionBucket} {
+			_, err := tx.CreateBucketIfNotExists(bucket)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &fileCache{db}, nil
+}
+
+func getVersion(tx *bolt.Tx, key string) uint64 {
+	version := tx.Bucket(fileCacheVersionBucket).Get([]byte(key))
+	if version == nil {
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(version)
+}
+
+func put(tx *bolt.Tx, key string, value []byte, version uint64) error {
+	err := tx.Bucket(fileCacheBucket).Put([]byte(key), value)
+	if err != nil {
+		return err
+	}
+
+	rawVersion := make([]byte, 8)
+	binary.BigEndian.PutUint64(rawVersion, version+1)
+	return tx.Bucket(fileCacheVersionBucket).Put([]byte(key), rawVersion)
+}
+
+func (c *fileCache) Set(ctx context.Context, key string, value interface{}) error {
+	rawValue, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return put(tx, key, rawValue, getVersion(tx, key))
+	})
+}
+
+func (c *fileCache) Get(ctx context.Context, key string) (string, error) {
+	value, _, err := c.GetWithVersion(ctx, key)
+	return value, err
+}
+
+func (c *fileCache) Remove(ctx context.Context, key string) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		err := tx.Bucket(fileCacheBucket).Delete([]byte(key))
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket(fileCacheVersionBucket).Delete([]byte(key))
+	})
+}
+
+func (c *fileCache) CompareAndRemove(ctx context.Context, key string, version uint64) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		if getVersion(tx, key) != version {
+			return ErrVersionConflict
+		}
+
+		err := tx.Bucket(fileCacheBucket).Delete([]byte(key))
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket(fileCacheVersionBucket).Delete([]byte(key))
+	})
+}
+
+func (c *fileCache) List(ctx context.Context, prefix string) ([]string, error) {
+	result := []string{}
+	err := c.db.View(func(tx *bolt.Tx) error {
+		// bolt keeps the keys sorted so the keys with the prefix are adjacent
+		cursor := tx.Bucket(fileCacheBucket).Cursor()
+		for key, _ := cursor.Seek([]byte(prefix)); key != nil && bytes.HasPrefix(key, []byte(prefix)); key, _ = cursor.Next() {
+			result = append(result, string(key))
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+func (c *fileCache) GetWithVersion(ctx context.Context, key string) (string, uint64, error) {
+	var result string
+	var version uint64
+	err := c.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(fileCacheBucket).Get([]byte(key))
+		if value == nil {
+			return mc.ErrKeyNotFound
+		}
+
+		result = string(value)
+		version = getVersion(tx, key)
+		return nil
+	})
+
+	return result, version, err
+}
+
+func (c *fileCache) CompareAndSet(ctx context.Context, key string, value interface{}, version uint64) error {
+	rawValue, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		if getVersion(tx, key) != version {
+			return ErrVersionConflict
+		}
+
+		return put(tx, key, rawValue, version)
+	})
+}
@@ -0,0 +1,41 @@
+package workflow
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	mc "go-micro.dev/v4/cache"
+)
+
+func TestFileCache(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "sagawf.db")
+
+	cache, err := NewFileCache(path)
+	assert.NoError(t, err)
+
+	_, err = cache.Get(ctx, "workflow:index")
+	assert.Equal(t, mc.ErrKeyNotFound, err)
+
+	assert.NoError(t, cache.Set(ctx, "workflow:index", Index{ID: 1}))
+
+	value, err := cache.Get(ctx, "workflow:index")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"ID":1}`, value)
+
+	assert.NoError(t, cache.(*fileCache).db.Close())
+
+	// values survive reopening of the cache file
+	cache, err = NewFileCache(path)
+	assert.NoError(t, err)
+
+	value, err = cache.Get(ctx, "workflow:index")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"ID":1}`, value)
+
+	assert.NoError(t, cache.Remove(ctx, "workflow:index"))
+	_, err = cache.Get(ctx, "workflow:index")
+	assert.Equal(t, mc.ErrKeyNotFound, err)
+}
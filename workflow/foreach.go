@@ -0,0 +1,178 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+func (op *Operation) isMap() bool {
+	return op.ForEach != ""
+}
+
+func (op *Operation) isItem() bool {
+	return op.Parent != ""
+}
+
+// getItem returns the child operation processing the item of the map operation
+func (op *Operation) getItem(index int) Operation {
+	item := *op
+	item.Name = fmt.Sprintf("%s[%d]", op.Name, index)
+	item.ForEach = ""
+	item.Parent = op.Name
+	item.Index = index
+	return item
+}
+
+func (w *Workflow) getOperation(name string) (Operation, bool) {
+	for _, op := range w.Operations {
+		if op.Name == name {
+			return op, true
+		}
+	}
+
+	return Operation{}, false
+}
+
+func parseItems(expression string) (pathCondition, error) {
+	c, err := parseCondition(expression)
+	if err != nil {
+		return pathCondition{}, err
+	}
+
+	p, ok := c.(pathCondition)
+	if !ok {
+		return pathCondition{}, fmt.Errorf("%q is not a path", expression)
+	}
+
+	return p, nil
+}
+
+// getItems returns the array the map operation is expanded over
+func getItems(expression string, data map[string]map[string]interface{}) ([]interface{}, error) {
+	p, err := parseItems(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	value := p.eval(data)
+	if raw, ok := value.(string); ok {
+		var decoded interface{}
+		if json.Unmarshal([]byte(raw), &decoded) == nil {
+			value = decoded
+		}
+	}
+
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s is not an array", expression)
+	}
+
+	return items, nil
+}
+
+func (s *state) getInput(op Operation) interface{} {
+	if op.isItem() {
+		items := s.Items[op.Parent]
+		if op.Index < len(items) {
+			return items[op.Index]
+		}
+		return nil
+	}
+
+	return s.Data[op.From]
+}
+
+// setItemResult collects the results of the items into the list of the map operation
+func (s *state) setItemResult(op Operation, payload interface{}) {
+	results, _ := s.Data[op.To][op.Parent].([]interface{})
+	if len(results) != len(s.Items[op.Parent]) {
+		results = make([]interface{}, len(s.Items[op.Parent]))
+	}
+
+	results[op.Index] = payload
+	s.setData(op.To, op.Parent, results)
+}
+
+// settleItems completes the map operation when all its items are processed
+func (s *state) settleItems(parent Operation, isRollback bool) {
+	for i := range s.Items[parent.Name] {
+		item := parent.getItem(i)
+		if isRollback {
+			// only the completed items are compensated
+			if hasOp(s.Done, item, false) && !hasOp(s.Done, item, true) {
+				return
+			}
+		} else if !hasOp(s.Done, item, false) && !hasOp(s.Failed, item, false) {
+			return
+		}
+	}
+
+	removeOp(s.InProgress, parent, isRollback)
+	addOp(s.Done, parent, isRollback)
+}
+
+func (p *processor) completeItem(s *state, op OperationPayload) {
+	item := op.Operation
+
+	removeOp(s.InProgress, item, op.IsRollback)
+	delete(s.Deadlines, item.getKey(op.IsRollback))
+	addOp(s.Done, item, op.IsRollback)
+	if !op.IsRollback {
+		s.setItemResult(item, op.Payload)
+	}
+
+	if parent, found := p.workflow.getOperation(item.Parent); found {
+		s.settleItems(parent, op.IsRollback)
+	}
+}
+
+// spawnItems starts an operation per item, the map operation without items is completed at once
+func (p *processor) spawnItems(op Operation) error {
+	isRollback := p.state.IsRollback
+
+	var items []interface{}
+	var itemErr error
+	if !isRollback {
+		items, itemErr = getItems(op.ForEach, p.state.Data)
+	}
+
+	return p.commit(func(s *state) {
+		if !s.canSpawn(op, isRollback) {
+			return
+		}
+
+		spawned := []Operation{}
+
+		addOp(s.InProgress, op, isRollback)
+		s.Attempts[op.getKey(isRollback)] = 1
+		if itemErr != nil {
+			s.enqueue(WORKFLOW_OPERATION_FAILED, op.toPayload(s.ID, p.workflow, isRollback, itemErr.Error()))
+			return
+		}
+
+		if !isRollback {
+			s.Items[op.Name] = items
+		}
+
+		for i := range s.Items[op.Name] {
+			item := op.getItem(i)
+			if isRollback && (!hasOp(s.Done, item, false) || hasOp(s.Done, item, true)) {
+				continue
+			}
+
+			addOp(s.InProgress, item, isRollback)
+			s.Attempts[item.getKey(isRollback)] = 1
+			s.setDeadline(item, isRollback)
+			s.setStarted(item, isRollback)
+			spawned = append(spawned, item)
+		}
+
+		if len(spawned) == 0 {
+			s.enqueue(WORKFLOW_OPERATION_COMPLETED, op.toPayload(s.ID, p.workflow, isRollback, []interface{}{}))
+		}
+
+		for _, item := range spawned {
+			s.enqueue(WORKFLOW_OPERATION_START, item.toPayload(s.ID, p.workflow, isRollback, s.getInput(item)))
+		}
+	})
+}
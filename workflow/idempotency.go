@@ -0,0 +1,176 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	mc "go-micro.dev/v4/cache"
+)
+
+// IDEMPOTENCY_LEASE is the time the reserved id waits for its workflow to be started,
+// the key is reserved again after the lease if the workflow state is not saved
+const IDEMPOTENCY_LEASE = time.Minute
+
+const idempotencyKeyPrefix = "workflow:idempotency:"
+
+// IdempotencyRecord maps the idempotency key of the request to the workflow started by it
+type IdempotencyRecord struct {
+	ID      string
+	Expires time.Time
+	// Pending is set until the workflow of the reserved id is started
+	Pending bool `json:",omitempty"`
+	// Lease is the time the pending reservation is given up at
+	Lease time.Time `json:",omitempty"`
+}
+
+func getIdempotencyKey(key string) string {
+	return idempotencyKeyPrefix + key
+}
+
+func hasState(cache Cache, id string) (bool, error) {
+	s := state{
+		ID: id,
+	}
+
+	_, err := cache.Get(context.Background(), s.getCacheKey())
+	if err == mc.ErrKeyNotFound {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// ReserveIdempotentID returns the id of the workflow started with the key during the retention window
+// or reserves the id of the new workflow, found is true if the workflow is already started or is being started
+func ReserveIdempotentID(cache Cache, key string, retention time.Duration, now time.Time, reserve func() (string, error)) (string, bool, error) {
+	reserved := ""
+	found := false
+
+	var record IdempotencyRecord
+	err := updateValue(cache, getIdempotencyKey(key), func(raw string, exists bool) (interface{}, error) {
+		record = IdempotencyRecord{}
+		if exists {
+			err := json.Unmarshal([]byte(raw), &record)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		found = exists && now.Before(record.Expires)
+
+		// the reservation of the failed or crashed start is given up unless the workflow state is saved
+		if found && record.Pending && !now.Before(record.Lease) {
+			started, err := hasState(cache, record.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			found = started
+			record.Pending = !started
+		}
+
+		if found {
+			return record, nil
+		}
+
+		// the id is reserved once even if the record is updated concurrently
+		if reserved == "" {
+			id, err := reserve()
+			if err != nil {
+				return nil, err
+			}
+			reserved = id
+		}
+
+		record = IdempotencyRecord{
+			ID:      reserved,
+			Expires: now.Add(retention),
+			Pending: true,
+			Lease:   now.Add(IDEMPOTENCY_LEASE),
+		}
+		return record, nil
+	})
+
+	if err != nil {
+		return "", false, err
+	}
+
+	return record.ID, found, nil
+}
+
+// ConfirmIdempotentID marks the reserved id as started
+func ConfirmIdempotentID(cache Cache, key string, id string) error {
+	return updateValue(cache, getIdempotencyKey(key), func(raw string, exists bool) (interface{}, error) {
+		if !exists {
+			return nil, fmt.Errorf("idempotency key %s is not reserved", key)
+		}
+
+		var record IdempotencyRecord
+		err := json.Unmarshal([]byte(raw), &record)
+		if err != nil {
+			return nil, err
+		}
+
+		if record.ID == id {
+			record.Pending = false
+		}
+		return record, nil
+	})
+}
+
+// ReleaseIdempotentID removes the reservation of the workflow which is failed to start
+func ReleaseIdempotentID(cache Cache, key string, id string) error {
+	return removeRecord(cache, getIdempotencyKey(key), func(record IdempotencyRecord) bool {
+		return record.ID == id && record.Pending
+	})
+}
+
+// RemoveExpiredIdempotencyRecords removes the records of the keys after their retention window
+func RemoveExpiredIdempotencyRecords(cache Cache, now time.Time) error {
+	keys, err := cache.List(context.Background(), idempotencyKeyPrefix)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		err := removeRecord(cache, key, func(record IdempotencyRecord) bool {
+			return !now.Before(record.Expires)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeRecord removes the record if it matches, the record updated concurrently is kept
+func removeRecord(cache Cache, key string, isRemoved func(record IdempotencyRecord) bool) error {
+	ctx := context.Background()
+
+	raw, version, err := cache.GetWithVersion(ctx, key)
+	if err == mc.ErrKeyNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var record IdempotencyRecord
+	err = json.Unmarshal([]byte(raw), &record)
+	if err != nil {
+		return err
+	}
+
+	if !isRemoved(record) {
+		return nil
+	}
+
+	err = cache.CompareAndRemove(ctx, key, version)
+	if err == ErrVersionConflict {
+		return nil
+	}
+
+	return err
+}
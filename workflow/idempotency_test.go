@@ -0,0 +1,96 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReserveIdempotentID(t *testing.T) {
+	cache := NewCacheMock()
+	reserve := func() (string, error) {
+		return ReserveID("workflow:index", cache)
+	}
+
+	now := time.Now()
+	id, found, err := ReserveIdempotentID(cache, "order-1", time.Hour, now, reserve)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", id)
+	assert.False(t, found)
+	assert.NoError(t, ConfirmIdempotentID(cache, "order-1", id))
+
+	// the retried request gets the same workflow
+	id, found, err = ReserveIdempotentID(cache, "order-1", time.Hour, now.Add(time.Minute), reserve)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", id)
+	assert.True(t, found)
+
+	id, found, err = ReserveIdempotentID(cache, "order-2", time.Hour, now, reserve)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", id)
+	assert.False(t, found)
+
+	// the key is reused after the retention window
+	id, found, err = ReserveIdempotentID(cache, "order-1", time.Hour, now.Add(2*time.Hour), reserve)
+	assert.NoError(t, err)
+	assert.Equal(t, "3", id)
+	assert.False(t, found)
+}
+
+func TestReserveIdempotentIDLease(t *testing.T) {
+	cache := NewCacheMock()
+	reserve := func() (string, error) {
+		return ReserveID("workflow:index", cache)
+	}
+
+	now := time.Now()
+	id, found, err := ReserveIdempotentID(cache, "order-1", time.Hour, now, reserve)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", id)
+	assert.False(t, found)
+
+	// the concurrent request waits for the workflow which is being started
+	id, found, err = ReserveIdempotentID(cache, "order-1", time.Hour, now.Add(time.Second), reserve)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", id)
+	assert.True(t, found)
+
+	// the workflow is not started before the lease is over so the key is reserved again
+	id, found, err = ReserveIdempotentID(cache, "order-1", time.Hour, now.Add(IDEMPOTENCY_LEASE), reserve)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", id)
+	assert.False(t, found)
+
+	// the workflow started before the crash is found after the lease
+	s := state{
+		ID: "2",
+	}
+	assert.NoError(t, s.init(cache, "s1", nil))
+	id, found, err = ReserveIdempotentID(cache, "order-1", time.Hour, now.Add(2*IDEMPOTENCY_LEASE), reserve)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", id)
+	assert.True(t, found)
+
+	// the failed start releases the key
+	id, _, err = ReserveIdempotentID(cache, "order-2", time.Hour, now, reserve)
+	assert.NoError(t, err)
+	assert.NoError(t, ReleaseIdempotentID(cache, "order-2", id))
+	_, found, err = ReserveIdempotentID(cache, "order-2", time.Hour, now, reserve)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	// the confirmed key is kept until the retention window is over
+	assert.NoError(t, ConfirmIdempotentID(cache, "order-1", "2"))
+	assert.NoError(t, ReleaseIdempotentID(cache, "order-1", "2"))
+	assert.NoError(t, RemoveExpiredIdempotencyRecords(cache, now.Add(time.Minute)))
+	keys, err := cache.List(context.Background(), idempotencyKeyPrefix)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{getIdempotencyKey("order-1"), getIdempotencyKey("order-2")}, keys)
+
+	assert.NoError(t, RemoveExpiredIdempotencyRecords(cache, now.Add(2*time.Hour)))
+	keys, err = cache.List(context.Background(), idempotencyKeyPrefix)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, keys)
+}
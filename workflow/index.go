@@ -1,34 +1,49 @@
 package workflow
 
 import (
-	"context"
 	"encoding/json"
+	"strconv"
 
-	mc "go-micro.dev/v4/cache"
+	"github.com/google/uuid"
 )
 
+const WORKFLOW_INDEX_KEY = "workflow:index"
+
 type Index struct {
 	ID int
 }
 
-func ReserveID(key string, cache Cache) (int, error) {
+func ReserveID(key string, cache Cache) (string, error) {
 	var index Index
 
-	ctx := context.Background()
-	rawIndex, err := cache.Get(ctx, key)
-
-	if err == mc.ErrKeyNotFound {
+	err := updateValue(cache, key, func(raw string, found bool) (interface{}, error) {
 		index = Index{
 			ID: 0,
 		}
-	} else if err != nil {
-		return index.ID, err
-	} else {
-		json.Unmarshal([]byte(rawIndex), &index)
+
+		if found {
+			err := json.Unmarshal([]byte(raw), &index)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		index.ID++
+		return index, nil
+	})
+
+	if err != nil {
+		return "", err
 	}
 
-	index.ID++
+	return strconv.Itoa(index.ID), nil
+}
+
+func ReserveUUID() (string, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return "", err
+	}
 
-	cache.Set(ctx, key, index)
-	return index.ID, nil
+	return id.String(), nil
 }
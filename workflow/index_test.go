@@ -0,0 +1,78 @@
+package workflow
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReserveID(t *testing.T) {
+	cache := NewCache()
+
+	var mu sync.Mutex
+	ids := make(map[string]bool)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			id, err := ReserveID("workflow:index", cache)
+			assert.NoError(t, err)
+
+			mu.Lock()
+			ids[id] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, ids, 50)
+
+	id, err := ReserveID("workflow:index", cache)
+	assert.NoError(t, err)
+	assert.Equal(t, "51", id)
+}
+
+func TestReserveUUID(t *testing.T) {
+	id1, err := ReserveUUID()
+	assert.NoError(t, err)
+
+	id2, err := ReserveUUID()
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, id1, id2)
+}
+
+func TestActiveIDs(t *testing.T) {
+	cache := NewCache()
+
+	// concurrent starts and ends do not conflict on a shared key
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+
+			assert.NoError(t, setActive(cache, id, true))
+			assert.NoError(t, setActive(cache, id, false))
+			assert.NoError(t, setActive(cache, id, true))
+		}(strconv.Itoa(i))
+	}
+	wg.Wait()
+
+	ids, err := GetActiveIDs(cache)
+	assert.NoError(t, err)
+	assert.Len(t, ids, 200)
+
+	assert.NoError(t, setActive(cache, "7", false))
+	assert.NoError(t, setActive(cache, "7", false))
+
+	ids, err = GetActiveIDs(cache)
+	assert.NoError(t, err)
+	assert.Len(t, ids, 199)
+	assert.NotContains(t, ids, "7")
+}
@@ -0,0 +1,73 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	mc "go-micro.dev/v4/cache"
+)
+
+const leaseKeyPrefix = "workflow:lease:"
+
+// RenewLease extends the lease of the coordinator, the operations claimed by the coordinator
+// are not recovered by the other coordinators while its lease is not expired
+func RenewLease(cache Cache, owner string, expires time.Time) error {
+	return cache.Set(context.Background(), leaseKeyPrefix+owner, expires)
+}
+
+func getLease(cache Cache, key string) (time.Time, bool, error) {
+	raw, err := cache.Get(context.Background(), key)
+	if err == mc.ErrKeyNotFound {
+		return time.Time{}, false, nil
+	} else if err != nil {
+		return time.Time{}, false, err
+	}
+
+	var expires time.Time
+	err = json.Unmarshal([]byte(raw), &expires)
+	return expires, err == nil, err
+}
+
+func isLeaseAlive(cache Cache, owner string, now time.Time) (bool, error) {
+	expires, found, err := getLease(cache, leaseKeyPrefix+owner)
+	return found && now.Before(expires), err
+}
+
+// RemoveExpiredLeases removes the leases of the stopped coordinators
+func RemoveExpiredLeases(cache Cache, now time.Time) error {
+	keys, err := cache.List(context.Background(), leaseKeyPrefix)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		expires, found, err := getLease(cache, key)
+		if err != nil {
+			return err
+		}
+
+		if found && !now.Before(expires) {
+			err = cache.Remove(context.Background(), key)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Claim records the coordinator executing the operation attempt
+func (p *processor) Claim(op OperationPayload, owner string) error {
+	p.state = state{
+		ID: op.ID,
+	}
+
+	return p.state.update(p.cache, func(s *state) {
+		key := op.Operation.getKey(op.IsRollback)
+		if hasOp(s.InProgress, op.Operation, op.IsRollback) && op.Attempt == s.Attempts[key] {
+			s.Owners[key] = owner
+		}
+	})
+}
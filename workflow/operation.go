@@ -1,23 +1,53 @@
 package workflow
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type Operation struct {
-	Name string `json:"name"`
-	From string `json:"from"`
-	To   string `json:"to"`
+	Name  string       `json:"name"`
+	From  string       `json:"from"`
+	To    string       `json:"to"`
+	Retry *RetryPolicy `json:"retry,omitempty"`
+	// CompensationRetry is applied when the rollback of the operation fails
+	CompensationRetry *RetryPolicy `json:"compensationRetry,omitempty"`
+	// Condition is evaluated over the workflow data, the operation is skipped if it is false
+	Condition string `json:"condition,omitempty"`
+	// ForEach is the path to the array the operation is expanded over, an operation is started per item
+	ForEach string `json:"forEach,omitempty"`
+	// Parent and Index identify the item of the map operation
+	Parent string `json:"parent,omitempty"`
+	Index  int    `json:"index,omitempty"`
+	// Workflow is the name of the definition started as a child workflow instead of the operation
+	Workflow        string `json:"workflow,omitempty"`
+	WorkflowVersion int    `json:"workflowVersion,omitempty"`
+	// Type selects the executor of the operation, Service is its target: a service name, an url or a func name
+	Type    string `json:"type,omitempty"`
+	Service string `json:"service,omitempty"`
+	// Timeout fails the operation if it is not completed in time
+	Timeout time.Duration `json:"timeout,omitempty"`
 }
 
 func (op *Operation) getKey(isRollback bool) string {
 	return fmt.Sprintf("%s:%s:%s:%v", op.Name, op.From, op.To, isRollback)
 }
 
-func (op *Operation) toPayload(id int, w Workflow, isRollback bool, payload interface{}) OperationPayload {
+func (op *Operation) getRetryPolicy(isRollback bool) *RetryPolicy {
+	if isRollback {
+		return op.CompensationRetry
+	}
+	return op.Retry
+}
+
+func (op *Operation) toPayload(id string, w Workflow, isRollback bool, payload interface{}) OperationPayload {
 	return OperationPayload{
 		ID:         id,
 		Name:       w.Name,
 		IsRollback: isRollback,
 		Payload:    payload,
 		Operation:  *op,
+		Attempt:    1,
+		EventID:    getEventID(id, *op, isRollback, 1),
 	}
 }
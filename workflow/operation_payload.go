@@ -1,9 +1,61 @@
 package workflow
 
+import (
+	"fmt"
+	"strings"
+)
+
 type OperationPayload struct {
-	ID         int
+	ID         string
 	IsRollback bool
 	Name       string
 	Operation  Operation
 	Payload    interface{}
+	Attempt    int
+	// EventID identifies the attempt of the operation, the result of the attempt carries the same id
+	EventID string
+}
+
+func getEventID(id string, op Operation, isRollback bool, attempt int) string {
+	return fmt.Sprintf("%s:%s:%d", id, op.getKey(isRollback), attempt)
+}
+
+func (p *OperationPayload) setAttempt(attempt int) {
+	p.Attempt = attempt
+	p.EventID = getEventID(p.ID, p.Operation, p.IsRollback, attempt)
+}
+
+// getEventID returns the id of the result, the result without it is identified by its attempt
+func (p *OperationPayload) getEventID() string {
+	if p.EventID != "" {
+		return p.EventID
+	}
+
+	return getEventID(p.ID, p.Operation, p.IsRollback, p.Attempt)
+}
+
+// setProcessed records the applied result so its redelivery is ignored
+func (s *state) setProcessed(op OperationPayload) {
+	s.Processed[op.getEventID()] = true
+}
+
+// isProcessed detects the duplicated delivery of the result and the stale result of the previous attempt
+func (s *state) isProcessed(op OperationPayload) bool {
+	if s.Processed[op.getEventID()] || !hasOp(s.InProgress, op.Operation, op.IsRollback) {
+		return true
+	}
+
+	return op.Attempt < s.Attempts[op.Operation.getKey(op.IsRollback)]
+}
+
+// forget removes the processed events of the operation so it can be spawned from the first attempt again
+func (s *state) forget(op Operation, isRollback bool) {
+	prefix := getEventID(s.ID, op, isRollback, 0)
+	prefix = prefix[:len(prefix)-1]
+
+	for id := range s.Processed {
+		if strings.HasPrefix(id, prefix) {
+			delete(s.Processed, id)
+		}
+	}
 }
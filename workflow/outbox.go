@@ -0,0 +1,110 @@
+package workflow
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OUTBOX_RELAY_DELAY is the time the message waits for the relay of its change before it is relayed by the timer
+const OUTBOX_RELAY_DELAY = 5 * time.Second
+
+// OutboxMessage is the message saved with the state change it is caused by, it is removed when it is published
+type OutboxMessage struct {
+	ID    int
+	Topic string
+	Body  json.RawMessage
+	// Created is the time the message is enqueued at
+	Created time.Time
+}
+
+// enqueue adds the message to the outbox, it is published by the relay once the state is saved
+func (s *state) enqueue(topic string, message interface{}) {
+	body, err := json.Marshal(message)
+	if err != nil {
+		s.err = err
+		return
+	}
+
+	s.OutboxSeq++
+	s.Outbox = append(s.Outbox, OutboxMessage{
+		ID:      s.OutboxSeq,
+		Topic:   topic,
+		Body:    body,
+		Created: time.Now(),
+	})
+}
+
+// commit saves the state change with the messages it enqueues and relays them
+func (p *processor) commit(change func(*state)) error {
+	seq := 0
+	err := p.state.update(p.cache, func(s *state) {
+		seq = s.OutboxSeq
+		change(s)
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.relay(func(m OutboxMessage) bool {
+		return m.ID > seq
+	})
+}
+
+// Relay publishes the messages left in the outbox by a broker failure,
+// the messages of the changes which are being relayed now are skipped
+func (p *processor) Relay(id string, now time.Time) error {
+	p.state = state{
+		ID: id,
+	}
+	err := p.state.load(p.cache)
+	if err != nil {
+		return err
+	}
+
+	before := now.Add(-OUTBOX_RELAY_DELAY)
+	return p.relay(func(m OutboxMessage) bool {
+		return !m.Created.After(before)
+	})
+}
+
+func relayAll(m OutboxMessage) bool {
+	return true
+}
+
+// relay publishes the selected outbox messages and removes the published ones,
+// a message is published again if the coordinator is stopped before it is removed
+func (p *processor) relay(isRelayed func(m OutboxMessage) bool) error {
+	sent := make(map[int]bool)
+
+	var err error
+	for _, m := range p.state.Outbox {
+		if !isRelayed(m) {
+			continue
+		}
+
+		err = p.producer.SendMessage(m.Topic, m.Body)
+		if err != nil {
+			break
+		}
+		sent[m.ID] = true
+	}
+
+	if len(sent) == 0 {
+		return err
+	}
+
+	updateErr := p.state.update(p.cache, func(s *state) {
+		pending := []OutboxMessage{}
+		for _, m := range s.Outbox {
+			if !sent[m.ID] {
+				pending = append(pending, m)
+			}
+		}
+		s.Outbox = pending
+	})
+	if err != nil {
+		return err
+	}
+
+	return updateErr
+}
@@ -1,11 +1,14 @@
 package workflow
 
+import "time"
+
 const (
 	WORKFLOW_OPERATION_START     = "wfos"
 	WORKFLOW_OPERATION_COMPLETED = "wfoc"
 	WORKFLOW_OPERATION_FAILED    = "wfof"
 	WORKFLOW_COMPLETED           = "wfc"
 	WORKFLOW_ROLLBACKED          = "wfr"
+	WORKFLOW_STUCK               = "wfst"
 	WORKFLOW_START               = "wfs"
 )
 
@@ -18,6 +21,8 @@ type processor struct {
 	producer Producer
 	workflow Workflow
 	state    state
+	// reserved are the ids of the child workflows reserved by the changes which may be retried on a conflict
+	reserved map[string]string
 }
 
 func NewProcessor(cache Cache, producer Producer) Processor {
@@ -28,22 +33,44 @@ func NewProcessor(cache Cache, producer Producer) Processor {
 }
 
 type Processor interface {
-	StartWorkflow(w Workflow, id int) error
+	StartWorkflow(w Workflow, id string) error
 	OnComplete(w Workflow, op OperationPayload) error
 	OnFailure(w Workflow, op OperationPayload) error
+	OnTimeout(w Workflow, id string, now time.Time) error
+	Redrive(w Workflow, id string) error
+	Compensate(w Workflow, id string) error
+	Recover(w Workflow, id string, grace time.Duration, now time.Time) error
+	Relay(id string, now time.Time) error
+	Claim(op OperationPayload, owner string) error
+	Cancel(w Workflow, id string) error
+	Pause(w Workflow, id string) error
+	Continue(w Workflow, id string) error
+	ForceComplete(w Workflow, id string, name string, isRollback bool, payload interface{}) error
+	Skip(w Workflow, id string, name string, isRollback bool) error
 }
 
-func (p *processor) StartWorkflow(w Workflow, id int) error {
+func (p *processor) StartWorkflow(w Workflow, id string) error {
 	p.workflow = w
 	p.state = state{
 		ID: id,
 	}
-	err := p.state.init(p.cache, w.Start, w.Payload)
+	if w.Timeout > 0 {
+		p.state.Deadline = time.Now().Add(w.Timeout)
+	}
+	p.state.Parent = w.Parent
+
+	// the child given up by its parent operation before its start has nothing to rollback
+	isAbandoned, err := isAbandoned(p.cache, w.Parent, id)
+	if err != nil || isAbandoned {
+		return err
+	}
+
+	err = p.state.init(p.cache, w.Start, w.Payload)
 	if err != nil {
 		return err
 	}
 
-	t := createDirectTracer(w, p.state, p.endWorkflow, p.spawnOperation)
+	t := createDirectTracer(w, &p.state, p.endWorkflow, p.spawnOperation, p.skipOperation)
 	return t.resolveWorkflow(w.Start)
 }
 
@@ -53,90 +80,222 @@ func (p *processor) OnComplete(w Workflow, op OperationPayload) error {
 	p.state = state{
 		ID: op.ID,
 	}
+
+	isProcessed := false
 	err := p.state.update(p.cache, func(s *state) {
+		isProcessed = s.isProcessed(op)
+		if isProcessed {
+			return
+		}
+		s.setProcessed(op)
+
+		if op.Operation.isItem() {
+			p.completeItem(s, op)
+			return
+		}
+
 		removeOp(s.InProgress, op.Operation, op.IsRollback)
+		delete(s.Deadlines, op.Operation.getKey(op.IsRollback))
 		addOp(s.Done, op.Operation, op.IsRollback)
 		s.setData(op.Operation.To, op.Operation.Name, op.Payload)
 	})
 
-	if err != nil {
+	if err != nil || isProcessed {
 		return err
 	}
 
-	if p.state.IsRollback {
-		t := createReverseTracer(w, p.state, p.endWorkflow, p.spawnOperation)
-		return t.resolveWorkflow(w.End)
-	} else {
-		t := createDirectTracer(w, p.state, p.endWorkflow, p.spawnOperation)
-		return t.resolveWorkflow(w.Start)
+	err = p.resolve()
+	if err != nil {
+		return err
 	}
+
+	// the last compensation in progress leaves the workflow stuck if a sibling compensation is failed
+	return p.checkStuck()
 }
 
 func (p *processor) OnFailure(w Workflow, op OperationPayload) error {
+	if op.IsRollback {
+		return p.onCompensationFailure(w, op)
+	}
+
 	p.workflow = w
 
 	p.state = state{
 		ID: op.ID,
 	}
-	err := p.state.update(p.cache, func(s *state) {
+
+	isProcessed := false
+	isRetried := false
+	abandoned := ""
+	err := p.commit(func(s *state) {
+		isProcessed = s.isProcessed(op)
+		if isProcessed {
+			return
+		}
+		s.setProcessed(op)
+
 		removeOp(s.InProgress, op.Operation, false)
 
-		s.IsRollback = true
+		// the child of the failed attempt is kept before the retry starts another one
+		abandoned = ""
+		if op.Operation.isWorkflow() && s.Children[op.Operation.Name] != "" {
+			abandoned = s.Children[op.Operation.Name]
+			s.abandon(op.Operation, abandoned)
+		}
+
+		key := op.Operation.getKey(false)
+		delete(s.Deadlines, key)
+		// a transient failure is retried unless the workflow is already rollbacked,
+		// the retry policy of the map operation applies to its items and not to the failed expansion
+		if !s.IsRollback && !op.Operation.isMap() && op.Operation.Retry.canRetry(s.Attempts[key]) {
+			s.Attempts[key]++
+			isRetried = true
+			addOp(s.InProgress, op.Operation, false)
+			s.setDeadline(op.Operation, false)
+			s.setStarted(op.Operation, false)
+			p.retryOperation(s, op.Operation, false, s.Attempts[key])
+		} else {
+			isRetried = false
+			addOp(s.Failed, op.Operation, false)
+			s.IsRollback = true
+
+			if parent, found := w.getOperation(op.Operation.Parent); found {
+				s.settleItems(parent, false)
+			}
+		}
 	})
 
 	if err != nil {
 		return err
 	}
 
-	t := createReverseTracer(w, p.state, p.endWorkflow, p.spawnOperation)
+	if isProcessed {
+		return nil
+	}
+
+	if abandoned != "" {
+		err = p.abandonChild(abandoned)
+		if err != nil {
+			return err
+		}
+	}
+
+	if isRetried {
+		return nil
+	}
+
+	t := createReverseTracer(w, &p.state, p.endWorkflow, p.spawnOperation)
 	return t.resolveWorkflow(w.End)
 }
 
+func (p *processor) resolve() error {
+	w := p.workflow
+
+	if p.state.IsRollback {
+		t := createReverseTracer(w, &p.state, p.endWorkflow, p.spawnOperation)
+		return t.resolveWorkflow(w.End)
+	} else {
+		t := createDirectTracer(w, &p.state, p.endWorkflow, p.spawnOperation, p.skipOperation)
+		return t.resolveWorkflow(w.Start)
+	}
+}
+
 func (p *processor) spawnOperation(op Operation) error {
+	if op.isMap() {
+		return p.spawnItems(op)
+	}
 
-	data := p.state.Data[op.From]
+	if op.isWorkflow() {
+		return p.spawnWorkflow(op)
+	}
+
+	isRollback := p.state.IsRollback
 
-	payload := OperationPayload{
-		ID:         p.state.ID,
-		IsRollback: p.state.IsRollback,
-		Name:       p.workflow.Name,
-		Operation:  op,
-		Payload:    data,
+	return p.commit(func(s *state) {
+		if !s.canSpawn(op, isRollback) {
+			return
+		}
+
+		addOp(s.InProgress, op, isRollback)
+		s.Attempts[op.getKey(isRollback)] = 1
+		s.setDeadline(op, isRollback)
+		s.setStarted(op, isRollback)
+		s.enqueue(WORKFLOW_OPERATION_START, op.toPayload(s.ID, p.workflow, isRollback, s.getInput(op)))
+	})
+}
+
+// canSpawn rejects the spawn decided on a stale state, e.g. by the concurrent resolution of another replica
+func (s *state) canSpawn(op Operation, isRollback bool) bool {
+	if s.Completed || s.IsPaused || s.IsRollback != isRollback {
+		return false
 	}
 
-	err := p.state.update(p.cache, func(s *state) {
-		addOp(s.InProgress, op, p.state.IsRollback)
+	if !isRollback && hasOp(s.Skipped, op, false) {
+		return false
+	}
+
+	return !hasOp(s.InProgress, op, isRollback) && !hasOp(s.Done, op, isRollback) && !hasOp(s.Failed, op, isRollback)
+}
+
+func (p *processor) skipOperation(op Operation) error {
+	return p.state.update(p.cache, func(s *state) {
+		addOp(s.Skipped, op, false)
 	})
+}
 
-	if err != nil {
-		return err
+func (p *processor) retryOperation(s *state, op Operation, isRollback bool, attempt int) {
+	if op.isWorkflow() {
+		p.startChild(s, op, isRollback)
+		return
+	}
+
+	// the delayed attempt is started by the timer once it is due
+	backoff := op.getRetryPolicy(isRollback).getBackoff(attempt)
+	if backoff > 0 {
+		s.setRetry(op, isRollback, time.Now().Add(backoff))
+		return
 	}
 
-	return p.producer.SendMessage(WORKFLOW_OPERATION_START, payload)
+	p.startAttempt(s, op, isRollback)
+}
+
+func (p *processor) startAttempt(s *state, op Operation, isRollback bool) {
+	key := op.getKey(isRollback)
+	payload := op.toPayload(s.ID, p.workflow, isRollback, s.getInput(op))
+	payload.setAttempt(s.Attempts[key])
+
+	s.enqueue(WORKFLOW_OPERATION_START, payload)
 }
 
 func (p *processor) endWorkflow() error {
 	if !p.state.Completed {
-		err := p.state.update(p.cache, func(s *state) {
+		err := p.commit(func(s *state) {
+			// the result is enqueued once even if the workflow is ended concurrently
+			if s.Completed {
+				return
+			}
 			s.Completed = true
+
+			payload := WorkflowPayload{
+				ID:         s.ID,
+				IsRollback: s.IsRollback,
+				Name:       p.workflow.Name,
+				Data:       s.Data,
+			}
+
+			topic := WORKFLOW_COMPLETED
+			if s.IsRollback {
+				topic = WORKFLOW_ROLLBACKED
+			}
+
+			s.enqueue(topic, payload)
+			p.notifyParent(s)
 		})
 		if err != nil {
 			return err
 		}
 
-		payload := WorkflowPayload{
-			ID:         p.state.ID,
-			IsRollback: p.state.IsRollback,
-			Name:       p.workflow.Name,
-			Data:       p.state.Data,
-		}
-
-		topic := WORKFLOW_COMPLETED
-		if p.state.IsRollback {
-			topic = WORKFLOW_ROLLBACKED
-		}
-
-		return p.producer.SendMessage(topic, payload)
+		return setActive(p.cache, p.state.ID, false)
 	}
 
 	return nil
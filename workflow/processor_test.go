@@ -1,7 +1,10 @@
 package workflow
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -47,37 +50,37 @@ func TestProcessor(t *testing.T) {
 			steps: []step{
 				{
 					action: func(t *testing.T, w Workflow, p *processor) {
-						err := p.StartWorkflow(w, 1)
+						err := p.StartWorkflow(w, "1")
 						assert.NoError(t, err)
 					},
 					validate: func(t *testing.T, w Workflow, p *ProducerMock) {
 						payload := make(map[string]interface{})
 						payload["input"] = nil
-						op1 := ops[0].toPayload(1, w, false, payload)
+						op1 := ops[0].toPayload("1", w, false, payload)
 						assert.True(t, p.Has(WORKFLOW_OPERATION_START, op1))
 
-						op2 := ops[1].toPayload(1, w, false, payload)
+						op2 := ops[1].toPayload("1", w, false, payload)
 						assert.True(t, p.Has(WORKFLOW_OPERATION_START, op2))
 					},
 				},
 				{
 					action: func(t *testing.T, w Workflow, p *processor) {
-						op1 := ops[0].toPayload(1, w, false, nil)
+						op1 := ops[0].toPayload("1", w, false, nil)
 						assert.NoError(t, p.OnComplete(w, op1))
 
-						op2 := ops[1].toPayload(1, w, false, nil)
+						op2 := ops[1].toPayload("1", w, false, nil)
 						assert.NoError(t, p.OnComplete(w, op2))
 					},
 					validate: func(t *testing.T, w Workflow, p *ProducerMock) {
 						payload := make(map[string]interface{})
 						payload["op2"] = nil
-						op3 := ops[2].toPayload(1, w, false, payload)
+						op3 := ops[2].toPayload("1", w, false, payload)
 						assert.True(t, p.Has(WORKFLOW_OPERATION_START, op3))
 					},
 				},
 				{
 					action: func(t *testing.T, w Workflow, p *processor) {
-						op3 := ops[2].toPayload(1, w, false, nil)
+						op3 := ops[2].toPayload("1", w, false, nil)
 						assert.NoError(t, p.OnComplete(w, op3))
 					},
 					validate: func(t *testing.T, w Workflow, p *ProducerMock) {
@@ -90,7 +93,7 @@ func TestProcessor(t *testing.T) {
 						data["s2"]["op1"] = nil
 						data["s2"]["op3"] = nil
 						data["s3"]["op2"] = nil
-						wp := w.toPayload(1, false, data)
+						wp := w.toPayload("1", false, data)
 						assert.True(t, p.Has(WORKFLOW_COMPLETED, wp))
 					},
 				},
@@ -101,55 +104,55 @@ func TestProcessor(t *testing.T) {
 			steps: []step{
 				{
 					action: func(t *testing.T, w Workflow, p *processor) {
-						err := p.StartWorkflow(w, 1)
+						err := p.StartWorkflow(w, "1")
 						assert.NoError(t, err)
 					},
 					validate: func(t *testing.T, w Workflow, p *ProducerMock) {
 						payload := make(map[string]interface{})
 						payload["input"] = nil
-						op1 := ops[0].toPayload(1, w, false, payload)
+						op1 := ops[0].toPayload("1", w, false, payload)
 						assert.True(t, p.Has(WORKFLOW_OPERATION_START, op1))
 
-						op2 := ops[1].toPayload(1, w, false, payload)
+						op2 := ops[1].toPayload("1", w, false, payload)
 						assert.True(t, p.Has(WORKFLOW_OPERATION_START, op2))
 					},
 				},
 				{
 					action: func(t *testing.T, w Workflow, p *processor) {
-						op1 := ops[0].toPayload(1, w, false, nil)
+						op1 := ops[0].toPayload("1", w, false, nil)
 						assert.NoError(t, p.OnComplete(w, op1))
 
-						op2 := ops[1].toPayload(1, w, false, nil)
+						op2 := ops[1].toPayload("1", w, false, nil)
 						assert.NoError(t, p.OnComplete(w, op2))
 					},
 					validate: func(t *testing.T, w Workflow, p *ProducerMock) {
 						payload := make(map[string]interface{})
 						payload["op2"] = nil
-						op3 := ops[2].toPayload(1, w, false, payload)
+						op3 := ops[2].toPayload("1", w, false, payload)
 						assert.True(t, p.Has(WORKFLOW_OPERATION_START, op3))
 					},
 				},
 				{
 					action: func(t *testing.T, w Workflow, p *processor) {
-						op3 := ops[2].toPayload(1, w, false, nil)
+						op3 := ops[2].toPayload("1", w, false, nil)
 						assert.NoError(t, p.OnFailure(w, op3))
 					},
 					validate: func(t *testing.T, w Workflow, p *ProducerMock) {
 						payload := make(map[string]interface{})
 						payload["input"] = nil
-						op1 := ops[0].toPayload(1, w, true, payload)
+						op1 := ops[0].toPayload("1", w, true, payload)
 						assert.True(t, p.Has(WORKFLOW_OPERATION_START, op1))
 
-						op2 := ops[1].toPayload(1, w, true, payload)
+						op2 := ops[1].toPayload("1", w, true, payload)
 						assert.True(t, p.Has(WORKFLOW_OPERATION_START, op2))
 					},
 				},
 				{
 					action: func(t *testing.T, w Workflow, p *processor) {
-						op1 := ops[0].toPayload(1, w, true, nil)
+						op1 := ops[0].toPayload("1", w, true, nil)
 						assert.NoError(t, p.OnComplete(w, op1))
 
-						op2 := ops[1].toPayload(1, w, true, nil)
+						op2 := ops[1].toPayload("1", w, true, nil)
 						assert.NoError(t, p.OnComplete(w, op2))
 					},
 					validate: func(t *testing.T, w Workflow, p *ProducerMock) {
@@ -161,7 +164,7 @@ func TestProcessor(t *testing.T) {
 						data["s1"]["input"] = nil
 						data["s2"]["op1"] = nil
 						data["s3"]["op2"] = nil
-						wp := w.toPayload(1, true, data)
+						wp := w.toPayload("1", true, data)
 						assert.True(t, p.Has(WORKFLOW_ROLLBACKED, wp))
 					},
 				},
@@ -172,31 +175,31 @@ func TestProcessor(t *testing.T) {
 			steps: []step{
 				{
 					action: func(t *testing.T, w Workflow, p *processor) {
-						err := p.StartWorkflow(w, 1)
+						err := p.StartWorkflow(w, "1")
 						assert.NoError(t, err)
 					},
 					validate: func(t *testing.T, w Workflow, p *ProducerMock) {
 						payload := make(map[string]interface{})
 						payload["input"] = nil
-						op1 := ops[0].toPayload(1, w, false, payload)
+						op1 := ops[0].toPayload("1", w, false, payload)
 						assert.True(t, p.Has(WORKFLOW_OPERATION_START, op1))
 
-						op2 := ops[1].toPayload(1, w, false, payload)
+						op2 := ops[1].toPayload("1", w, false, payload)
 						assert.True(t, p.Has(WORKFLOW_OPERATION_START, op2))
 					},
 				},
 				{
 					action: func(t *testing.T, w Workflow, p *processor) {
-						op1 := ops[0].toPayload(1, w, false, nil)
+						op1 := ops[0].toPayload("1", w, false, nil)
 						assert.NoError(t, p.OnComplete(w, op1))
 
-						op2 := ops[1].toPayload(1, w, false, nil)
+						op2 := ops[1].toPayload("1", w, false, nil)
 						assert.NoError(t, p.OnFailure(w, op2))
 					},
 					validate: func(t *testing.T, w Workflow, p *ProducerMock) {
 						payload := make(map[string]interface{})
 						payload["input"] = nil
-						op1 := ops[0].toPayload(1, w, true, payload)
+						op1 := ops[0].toPayload("1", w, true, payload)
 						assert.True(t, p.Has(WORKFLOW_OPERATION_START, op1))
 					},
 				},
@@ -207,32 +210,32 @@ func TestProcessor(t *testing.T) {
 			steps: []step{
 				{
 					action: func(t *testing.T, w Workflow, p *processor) {
-						err := p.StartWorkflow(w, 1)
+						err := p.StartWorkflow(w, "1")
 						assert.NoError(t, err)
 					},
 					validate: func(t *testing.T, w Workflow, p *ProducerMock) {
 						payload := make(map[string]interface{})
 						payload["input"] = nil
 
-						op1 := ops[0].toPayload(1, w, false, payload)
+						op1 := ops[0].toPayload("1", w, false, payload)
 						assert.True(t, p.Has(WORKFLOW_OPERATION_START, op1))
 
-						op2 := ops[1].toPayload(1, w, false, payload)
+						op2 := ops[1].toPayload("1", w, false, payload)
 						assert.True(t, p.Has(WORKFLOW_OPERATION_START, op2))
 					},
 				},
 				{
 					action: func(t *testing.T, w Workflow, p *processor) {
-						op1 := ops[0].toPayload(1, w, false, nil)
+						op1 := ops[0].toPayload("1", w, false, nil)
 						assert.NoError(t, p.OnFailure(w, op1))
 
-						op2 := ops[1].toPayload(1, w, false, nil)
+						op2 := ops[1].toPayload("1", w, false, nil)
 						assert.NoError(t, p.OnComplete(w, op2))
 					},
 					validate: func(t *testing.T, w Workflow, p *ProducerMock) {
 						payload := make(map[string]interface{})
 						payload["input"] = nil
-						op2 := ops[1].toPayload(1, w, true, payload)
+						op2 := ops[1].toPayload("1", w, true, payload)
 						assert.True(t, p.Has(WORKFLOW_OPERATION_START, op2))
 					},
 				},
@@ -240,20 +243,1185 @@ func TestProcessor(t *testing.T) {
 		},
 	}
 
+	run := func(t *testing.T, w Workflow, steps []step, duplicated bool) (*ProducerMock, state) {
+		cache := NewCacheMock()
+		producer := NewProducerMock()
+
+		for i, step := range steps {
+			proc := &processor{
+				cache:    cache,
+				producer: producer,
+			}
+			step.action(t, w, proc)
+			// the broker delivers the operation results once more
+			if duplicated && i > 0 {
+				step.action(t, w, proc)
+			}
+			step.validate(t, w, producer)
+		}
+
+		s := state{
+			ID: "1",
+		}
+		assert.NoError(t, s.load(cache))
+
+		return producer, s
+	}
+
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			cache := NewCacheMock()
-			producer := NewProducerMock()
-
-			for _, step := range tc.steps {
-				proc := &processor{
-					cache:    cache,
-					producer: producer,
-				}
-				step.action(t, tc.w, proc)
-				step.validate(t, tc.w, producer)
-			}
+			producer, s := run(t, tc.w, tc.steps, false)
+			duplicated, ds := run(t, tc.w, tc.steps, true)
+
+			// duplicates change neither the state nor the published messages
+			assert.Equal(t, producer.messages, duplicated.messages)
+			s.Started, ds.Started = nil, nil
+			assert.Equal(t, s, ds)
 		})
 	}
+}
+
+func TestProcessorStaleEvents(t *testing.T) {
+	ops := []Operation{
+		{
+			Name:  "op1",
+			From:  "s1",
+			To:    "s2",
+			Retry: &RetryPolicy{MaxAttempts: 2},
+		},
+		{
+			Name: "op2",
+			From: "s1",
+			To:   "s2",
+		},
+	}
+
+	w := Workflow{
+		Name:       "default workflow",
+		Start:      "s1",
+		End:        "s2",
+		Operations: ops,
+	}
+
+	cache := NewCacheMock()
+	producer := NewProducerMock()
+	proc := NewProcessor(cache, producer)
+	assert.NoError(t, proc.StartWorkflow(w, "1"))
+
+	load := func() state {
+		s := state{
+			ID: "1",
+		}
+		assert.NoError(t, s.load(cache))
+		return s
+	}
+
+	// the first attempt of op1 is failed and retried, its late result is stale
+	assert.NoError(t, proc.OnFailure(w, ops[0].toPayload("1", w, false, nil)))
+	assert.NoError(t, proc.OnComplete(w, ops[0].toPayload("1", w, false, "late")))
+
+	s := load()
+	assert.Equal(t, 2, s.Attempts[ops[0].getKey(false)])
+	assert.True(t, hasOp(s.InProgress, ops[0], false))
+	assert.Empty(t, s.Data["s2"])
+
+	// op2 is failed, its redelivered failure and a completion after the failure are ignored
+	failed := ops[1].toPayload("1", w, false, nil)
+	assert.NoError(t, proc.OnFailure(w, failed))
+	before := load()
+
+	assert.NoError(t, proc.OnFailure(w, failed))
+	assert.NoError(t, proc.OnComplete(w, ops[1].toPayload("1", w, false, "late")))
+	assert.Equal(t, before, load())
+
+	// the second attempt of op1 is completed and compensated
+	op1 := ops[0].toPayload("1", w, false, "done")
+	op1.setAttempt(2)
+	assert.NoError(t, proc.OnComplete(w, op1))
+	assert.NoError(t, proc.OnComplete(w, op1))
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_START, ops[0].toPayload("1", w, true, map[string]interface{}{"input": nil})))
+
+	assert.NoError(t, proc.OnComplete(w, ops[0].toPayload("1", w, true, nil)))
+	s = load()
+	assert.True(t, s.Completed)
+	assert.True(t, s.IsRollback)
+}
+
+func TestProcessorEventsWithoutID(t *testing.T) {
+	ops := []Operation{
+		{
+			Name: "op1",
+			From: "s1",
+			To:   "s2",
+		},
+		{
+			Name: "op2",
+			From: "s1",
+			To:   "s2",
+		},
+	}
+
+	w := Workflow{
+		Name:       "default workflow",
+		Start:      "s1",
+		End:        "s2",
+		Operations: ops,
+	}
+
+	cache := NewCacheMock()
+	producer := NewProducerMock()
+	proc := NewProcessor(cache, producer)
+	assert.NoError(t, proc.StartWorkflow(w, "1"))
+
+	// the results of the producers which do not set the event id are identified by their attempts
+	for _, op := range ops {
+		payload := op.toPayload("1", w, false, nil)
+		payload.EventID = ""
+		assert.NoError(t, proc.OnComplete(w, payload))
+		assert.NoError(t, proc.OnComplete(w, payload))
+	}
+
+	s := state{
+		ID: "1",
+	}
+	assert.NoError(t, s.load(cache))
+	assert.True(t, s.Completed)
+	assert.NotContains(t, s.Processed, "")
+	assert.Len(t, producer.messages[WORKFLOW_COMPLETED], 1)
+}
+
+func TestProcessorResume(t *testing.T) {
+	ops := []Operation{
+		{
+			Name: "op1",
+			From: "s1",
+			To:   "s2",
+		},
+		{
+			Name: "op2",
+			From: "s1",
+			To:   "s3",
+		},
+		{
+			Name: "op3",
+			From: "s3",
+			To:   "s2",
+		},
+	}
+
+	w := Workflow{
+		Name:       "default workflow",
+		Start:      "s1",
+		End:        "s2",
+		Operations: ops,
+	}
+
+	cache := NewCacheMock()
+	producer := NewProducerMock()
+
+	proc := NewProcessor(cache, producer)
+	assert.NoError(t, proc.StartWorkflow(w, "1"))
+
+	ids, err := GetActiveIDs(cache)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1"}, ids)
+
+	// op1 and op2 are completed but the coordinator is stopped before op3 is spawned
+	s := state{
+		ID: "1",
+	}
+	assert.NoError(t, s.update(cache, func(s *state) {
+		for _, op := range ops[:2] {
+			removeOp(s.InProgress, op, false)
+			addOp(s.Done, op, false)
+			s.setData(op.To, op.Name, nil)
+		}
+	}))
+
+	producer = NewProducerMock()
+	proc = NewProcessor(cache, producer)
+	assert.NoError(t, proc.Recover(w, "1", time.Hour, time.Now()))
+
+	payload := make(map[string]interface{})
+	payload["op2"] = nil
+	op3 := ops[2].toPayload("1", w, false, payload)
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_START, op3))
+
+	assert.NoError(t, proc.OnComplete(w, op3))
+
+	ids, err = GetActiveIDs(cache)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, ids)
+}
+
+func TestProcessorRecover(t *testing.T) {
+	ops := []Operation{
+		{
+			Name: "op1",
+			From: "s1",
+			To:   "s2",
+		},
+		{
+			Name: "op2",
+			From: "s1",
+			To:   "s3",
+		},
+		{
+			Name: "op3",
+			From: "s3",
+			To:   "s2",
+		},
+	}
+
+	w := Workflow{
+		Name:       "default workflow",
+		Start:      "s1",
+		End:        "s2",
+		Operations: ops,
+	}
+
+	cache := NewCacheMock()
+	proc := NewProcessor(cache, NewProducerMock())
+	assert.NoError(t, proc.StartWorkflow(w, "1"))
+	assert.NoError(t, proc.StartWorkflow(w, "2"))
+	assert.NoError(t, proc.OnComplete(w, ops[0].toPayload("2", w, false, nil)))
+	assert.NoError(t, proc.OnComplete(w, ops[1].toPayload("2", w, false, nil)))
+	assert.NoError(t, proc.OnComplete(w, ops[2].toPayload("2", w, false, nil)))
+
+	// op2 of the first workflow is completed but the coordinator is stopped before op3 is spawned
+	s := state{
+		ID: "1",
+	}
+	assert.NoError(t, s.update(cache, func(s *state) {
+		removeOp(s.InProgress, ops[1], false)
+		addOp(s.Done, ops[1], false)
+		s.setData(ops[1].To, ops[1].Name, nil)
+	}))
+
+	// the coordinator is stopped before the state of the third workflow is saved
+	assert.NoError(t, setActive(cache, "3", true))
+
+	ids, err := GetActiveIDs(cache)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "3"}, ids)
+
+	payload := map[string]interface{}{"input": nil}
+	op1 := ops[0].toPayload("1", w, false, payload)
+	op3 := ops[2].toPayload("1", w, false, map[string]interface{}{"op2": nil})
+
+	// op1 is still in the grace period so only the resolution is repeated
+	producer := NewProducerMock()
+	proc = NewProcessor(cache, producer)
+	for _, id := range ids {
+		assert.NoError(t, proc.Recover(w, id, time.Minute, time.Now()))
+	}
+	assert.False(t, producer.Has(WORKFLOW_OPERATION_START, op1))
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_START, op3))
+	assert.Empty(t, producer.messages[WORKFLOW_COMPLETED])
+
+	// op1 is executed by the coordinator which is still alive
+	now := time.Now().Add(time.Hour)
+	assert.NoError(t, proc.Claim(ops[0].toPayload("1", w, false, nil), "peer"))
+	assert.NoError(t, RenewLease(cache, "peer", now.Add(time.Minute)))
+
+	producer = NewProducerMock()
+	proc = NewProcessor(cache, producer)
+	assert.NoError(t, proc.Recover(w, "1", time.Minute, now))
+	assert.False(t, producer.Has(WORKFLOW_OPERATION_START, op1))
+
+	// op1 start is published again when the lease of its coordinator is expired
+	assert.NoError(t, proc.Recover(w, "1", time.Minute, now.Add(time.Minute)))
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_START, op1))
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_START, op3))
+
+	assert.NoError(t, RemoveExpiredLeases(cache, now.Add(time.Minute)))
+	keys, err := cache.List(context.Background(), leaseKeyPrefix)
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+
+	active, err := GetActiveIDs(cache)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1"}, active)
+}
+
+func TestProcessorOutbox(t *testing.T) {
+	ops := []Operation{
+		{
+			Name: "op1",
+			From: "s1",
+			To:   "s2",
+		},
+		{
+			Name: "op2",
+			From: "s2",
+			To:   "s3",
+		},
+	}
+
+	w := Workflow{
+		Name:       "default workflow",
+		Start:      "s1",
+		End:        "s3",
+		Operations: ops,
+	}
+
+	cache := NewCacheMock()
+	producer := NewProducerMock()
+
+	proc := NewProcessor(cache, producer)
+	assert.NoError(t, proc.StartWorkflow(w, "1"))
+
+	s := state{
+		ID: "1",
+	}
+	assert.NoError(t, s.load(cache))
+	assert.Empty(t, s.Outbox)
+
+	// the broker is down when op1 is completed so op2 start is kept in the outbox
+	producer.Err = errors.New("broker is down")
+	op1 := ops[0].toPayload("1", w, false, "done")
+	assert.Equal(t, producer.Err, proc.OnComplete(w, op1))
+
+	payload := map[string]interface{}{"op1": "done"}
+	op2 := ops[1].toPayload("1", w, false, payload)
+
+	assert.NoError(t, s.load(cache))
+	assert.True(t, hasOp(s.InProgress, ops[1], false))
+	assert.Len(t, s.Outbox, 1)
+	assert.Equal(t, WORKFLOW_OPERATION_START, s.Outbox[0].Topic)
+
+	// the restarted coordinator publishes the pending message
+	producer = NewProducerMock()
+	proc = NewProcessor(cache, producer)
+	assert.NoError(t, proc.Recover(w, "1", time.Hour, time.Now()))
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_START, op2))
+
+	assert.NoError(t, s.load(cache))
+	assert.Empty(t, s.Outbox)
+
+	assert.NoError(t, proc.OnComplete(w, ops[1].toPayload("1", w, false, nil)))
+	assert.NoError(t, s.load(cache))
+	assert.True(t, s.Completed)
+	assert.Empty(t, s.Outbox)
+}
+
+func TestProcessorOutboxRelay(t *testing.T) {
+	op1 := Operation{
+		Name: "op1",
+		From: "s1",
+		To:   "s2",
+	}
+
+	w := Workflow{
+		Name:       "default workflow",
+		Start:      "s1",
+		End:        "s2",
+		Operations: []Operation{op1},
+	}
+
+	cache := NewCacheMock()
+	producer := NewProducerMock()
+	proc := NewProcessor(cache, producer)
+
+	// the broker is down when the workflow is started
+	producer.Err = errors.New("broker is down")
+	assert.Equal(t, producer.Err, proc.StartWorkflow(w, "1"))
+
+	now := time.Now()
+	assert.Equal(t, producer.Err, proc.Relay("1", now.Add(OUTBOX_RELAY_DELAY)))
+
+	// the message of the change which can be relayed by itself is not published by the timer
+	producer.Err = nil
+	assert.NoError(t, proc.Relay("1", now))
+	assert.Empty(t, producer.messages)
+
+	// the timer publishes the pending message once the broker is back without a restart
+	assert.NoError(t, proc.Relay("1", now.Add(OUTBOX_RELAY_DELAY)))
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_START, op1.toPayload("1", w, false, map[string]interface{}{"input": nil})))
+
+	s := state{
+		ID: "1",
+	}
+	assert.NoError(t, s.load(cache))
+	assert.Empty(t, s.Outbox)
+
+	assert.NoError(t, proc.OnComplete(w, op1.toPayload("1", w, false, nil)))
+	assert.Len(t, producer.messages[WORKFLOW_COMPLETED], 1)
+}
+
+func TestProcessorRetry(t *testing.T) {
+	retry := &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Second,
+		Multiplier:     2,
+	}
+
+	ops := []Operation{
+		{
+			Name:  "op1",
+			From:  "s1",
+			To:    "s2",
+			Retry: retry,
+		},
+		{
+			Name: "op2",
+			From: "s1",
+			To:   "s2",
+		},
+	}
+
+	w := Workflow{
+		Name:       "retry workflow",
+		Start:      "s1",
+		End:        "s2",
+		Operations: ops,
+	}
+
+	cache := NewCacheMock()
+	producer := NewProducerMock()
+	proc := NewProcessor(cache, producer)
+
+	assert.NoError(t, proc.StartWorkflow(w, "1"))
+
+	payload := make(map[string]interface{})
+	payload["input"] = nil
+
+	// op1 is retried with exponential backoff until attempts are exhausted
+	expected := []time.Duration{time.Second, 2 * time.Second}
+	for i, backoff := range expected {
+		failed := ops[0].toPayload("1", w, false, nil)
+		failed.setAttempt(i + 1)
+		now := time.Now()
+		assert.NoError(t, proc.OnFailure(w, failed))
+
+		// the attempt is started by the timer once the backoff is passed
+		op1 := ops[0].toPayload("1", w, false, payload)
+		op1.setAttempt(i + 2)
+		assert.NoError(t, proc.OnTimeout(w, "1", now.Add(backoff/2)))
+		assert.False(t, producer.Has(WORKFLOW_OPERATION_START, op1))
+		assert.NoError(t, proc.OnTimeout(w, "1", now.Add(backoff+time.Second)))
+		assert.True(t, producer.Has(WORKFLOW_OPERATION_START, op1))
+	}
+
+	s := state{
+		ID: "1",
+	}
+	assert.NoError(t, s.load(cache))
+	assert.Equal(t, 3, s.Attempts[ops[0].getKey(false)])
+	assert.False(t, s.IsRollback)
+
+	// op2 is completed and the last op1 failure starts the compensation
+	assert.NoError(t, proc.OnComplete(w, ops[1].toPayload("1", w, false, nil)))
+	failed := ops[0].toPayload("1", w, false, nil)
+	failed.setAttempt(3)
+	assert.NoError(t, proc.OnFailure(w, failed))
+
+	op2 := ops[1].toPayload("1", w, true, payload)
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_START, op2))
+}
+
+func TestProcessorCompensation(t *testing.T) {
+	ops := []Operation{
+		{
+			Name: "op1",
+			From: "s1",
+			To:   "s2",
+			CompensationRetry: &RetryPolicy{
+				MaxAttempts:    2,
+				InitialBackoff: time.Second,
+			},
+		},
+		{
+			Name: "op2",
+			From: "s1",
+			To:   "s2",
+		},
+	}
+
+	w := Workflow{
+		Name:       "compensation workflow",
+		Start:      "s1",
+		End:        "s2",
+		Operations: ops,
+	}
+
+	cache := NewCacheMock()
+	producer := NewProducerMock()
+	proc := NewProcessor(cache, producer)
+
+	assert.NoError(t, proc.StartWorkflow(w, "1"))
+	assert.NoError(t, proc.OnComplete(w, ops[0].toPayload("1", w, false, nil)))
+	assert.NoError(t, proc.OnFailure(w, ops[1].toPayload("1", w, false, nil)))
+
+	payload := make(map[string]interface{})
+	payload["input"] = nil
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_START, ops[0].toPayload("1", w, true, payload)))
+
+	// the compensation is retried with its own policy
+	assert.NoError(t, proc.OnFailure(w, ops[0].toPayload("1", w, true, nil)))
+
+	op1 := ops[0].toPayload("1", w, true, payload)
+	op1.setAttempt(2)
+	assert.False(t, producer.Has(WORKFLOW_OPERATION_START, op1))
+	assert.NoError(t, proc.OnTimeout(w, "1", time.Now().Add(2*time.Second)))
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_START, op1))
+
+	// the workflow is stuck when compensation retries are exhausted
+	failed := ops[0].toPayload("1", w, true, nil)
+	failed.setAttempt(2)
+	assert.NoError(t, proc.OnFailure(w, failed))
+
+	status, err := GetWorkflowStatus(cache, "1")
+	assert.NoError(t, err)
+	assert.True(t, status.IsStuck)
+	assert.False(t, status.Completed)
+	assert.Equal(t, []OperationStatus{}, status.InProgress)
+	assert.Equal(t, []OperationStatus{
+		{Operation: ops[0], IsRollback: true},
+		{Operation: ops[1], IsRollback: false},
+	}, status.Failed)
+
+	data := make(map[string]map[string]interface{})
+	data["s1"] = make(map[string]interface{})
+	data["s2"] = make(map[string]interface{})
+	data["s1"]["input"] = nil
+	data["s2"]["op1"] = nil
+	wp := w.toPayload("1", true, data)
+	wp.IsStuck = true
+	assert.True(t, producer.Has(WORKFLOW_STUCK, wp))
+
+	ids, err := GetActiveIDs(cache)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, ids)
+
+	// the operator redrives the remaining compensations
+	producer = NewProducerMock()
+	proc = NewProcessor(cache, producer)
+	assert.NoError(t, proc.Redrive(w, "1"))
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_START, ops[0].toPayload("1", w, true, payload)))
+	assert.Error(t, proc.Redrive(w, "1"))
+
+	assert.NoError(t, proc.OnComplete(w, ops[0].toPayload("1", w, true, nil)))
+	assert.True(t, producer.Has(WORKFLOW_ROLLBACKED, w.toPayload("1", true, data)))
+}
+
+func TestProcessorCompensationInProgress(t *testing.T) {
+	ops := []Operation{
+		{Name: "op1", From: "s1", To: "s2"},
+		{Name: "op2", From: "s1", To: "s2"},
+		{Name: "op3", From: "s1", To: "s2"},
+	}
+
+	w := Workflow{
+		Name:       "compensation workflow",
+		Start:      "s1",
+		End:        "s2",
+		Operations: ops,
+	}
+
+	cache := NewCacheMock()
+	producer := NewProducerMock()
+	proc := NewProcessor(cache, producer)
+
+	assert.NoError(t, proc.StartWorkflow(w, "1"))
+	assert.NoError(t, proc.OnComplete(w, ops[0].toPayload("1", w, false, nil)))
+	assert.NoError(t, proc.OnComplete(w, ops[1].toPayload("1", w, false, nil)))
+	assert.NoError(t, proc.OnFailure(w, ops[2].toPayload("1", w, false, nil)))
+
+	// the workflow is not stuck while the sibling compensation is in progress
+	assert.NoError(t, proc.OnFailure(w, ops[0].toPayload("1", w, true, nil)))
+
+	status, err := GetWorkflowStatus(cache, "1")
+	assert.NoError(t, err)
+	assert.False(t, status.IsStuck)
+	assert.Equal(t, []OperationStatus{{Operation: ops[1], IsRollback: true}}, status.InProgress)
+
+	ids, err := GetActiveIDs(cache)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1"}, ids)
+
+	// the last compensation in progress leaves the workflow stuck
+	assert.NoError(t, proc.OnComplete(w, ops[1].toPayload("1", w, true, nil)))
+
+	status, err = GetWorkflowStatus(cache, "1")
+	assert.NoError(t, err)
+	assert.True(t, status.IsStuck)
+	assert.False(t, status.Completed)
+	assert.Equal(t, []OperationStatus{}, status.InProgress)
+
+	ids, err = GetActiveIDs(cache)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, ids)
+}
+
+func TestProcessorSkippedEnd(t *testing.T) {
+	w, err := NewBuilder("payment workflow").
+		Start("s1").
+		Step("pay", "s1", "s2").
+		Step("charge", "s2", "s3", WithCondition(`s2.pay.method == "card"`)).
+		End("s3").
+		Build()
+	assert.NoError(t, err)
+
+	pay, charge := w.Operations[0], w.Operations[1]
+
+	cache := NewCacheMock()
+	producer := NewProducerMock()
+	proc := NewProcessor(cache, producer)
+
+	assert.NoError(t, proc.StartWorkflow(w, "1"))
+	assert.NoError(t, proc.OnComplete(w, pay.toPayload("1", w, false, `{"method":"cash"}`)))
+
+	// the end reached only by the skipped branch completes the workflow, the completed operations are kept
+	status, err := GetWorkflowStatus(cache, "1")
+	assert.NoError(t, err)
+	assert.True(t, status.Completed)
+	assert.False(t, status.IsRollback)
+	assert.Equal(t, []OperationStatus{{Operation: charge}}, status.Skipped)
+	assert.True(t, producer.Has(WORKFLOW_COMPLETED, w.toPayload("1", false, status.Data)))
+	assert.False(t, producer.Has(WORKFLOW_OPERATION_START, pay.toPayload("1", w, true, map[string]interface{}{"input": nil})))
+}
+
+func TestProcessorCondition(t *testing.T) {
+	w, err := NewBuilder("payment workflow").
+		Start("s1").
+		Step("pay", "s1", "s2").
+		Step("card", "s2", "s3", WithCondition(`s2.pay.method == "card"`)).
+		Step("cash", "s2", "s4", WithCondition(`s2.pay.method != "card"`)).
+		Step("charge", "s3", "s5").
+		Step("collect", "s4", "s5").
+		End("s5").
+		Build()
+	assert.NoError(t, err)
+
+	pay, card, cash, charge, collect := w.Operations[0], w.Operations[1], w.Operations[2], w.Operations[3], w.Operations[4]
+
+	cache := NewCacheMock()
+	producer := NewProducerMock()
+	proc := NewProcessor(cache, producer)
+
+	assert.NoError(t, proc.StartWorkflow(w, "1"))
+
+	op := pay.toPayload("1", w, false, `{"method":"card"}`)
+	assert.NoError(t, proc.OnComplete(w, op))
+
+	// the cash branch is skipped with all operations of the branch
+	payload := map[string]interface{}{"pay": `{"method":"card"}`}
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_START, card.toPayload("1", w, false, payload)))
+	assert.False(t, producer.Has(WORKFLOW_OPERATION_START, cash.toPayload("1", w, false, payload)))
+
+	status, err := GetWorkflowStatus(cache, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, []OperationStatus{
+		{Operation: cash, IsRollback: false},
+		{Operation: collect, IsRollback: false},
+	}, status.Skipped)
+
+	assert.NoError(t, proc.OnComplete(w, card.toPayload("1", w, false, nil)))
+	assert.NoError(t, proc.OnFailure(w, charge.toPayload("1", w, false, nil)))
+
+	// only the taken branch is compensated
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_START, card.toPayload("1", w, true, payload)))
+	assert.NoError(t, proc.OnComplete(w, card.toPayload("1", w, true, nil)))
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_START, pay.toPayload("1", w, true, map[string]interface{}{"input": nil})))
+	assert.NoError(t, proc.OnComplete(w, pay.toPayload("1", w, true, nil)))
+
+	status, err = GetWorkflowStatus(cache, "1")
+	assert.NoError(t, err)
+	assert.True(t, status.Completed)
+	assert.True(t, status.IsRollback)
+}
+
+func TestProcessorForEach(t *testing.T) {
+	w, err := NewBuilder("order workflow").
+		Start("s1").
+		Step("reserve", "s1", "s2", WithForEach("s1.input")).
+		End("s2").
+		Payload(`["a","b","c"]`).
+		Build()
+	assert.NoError(t, err)
+
+	reserve := w.Operations[0]
+	items := []interface{}{"a", "b", "c"}
+	item := func(i int, isRollback bool, payload interface{}) OperationPayload {
+		op := reserve.getItem(i)
+		return op.toPayload("1", w, isRollback, payload)
+	}
+
+	t.Run("results are collected", func(t *testing.T) {
+		cache := NewCacheMock()
+		producer := NewProducerMock()
+		proc := NewProcessor(cache, producer)
+
+		assert.NoError(t, proc.StartWorkflow(w, "1"))
+		for i, value := range items {
+			assert.True(t, producer.Has(WORKFLOW_OPERATION_START, item(i, false, value)))
+		}
+
+		for _, i := range []int{2, 0, 1} {
+			assert.NoError(t, proc.OnComplete(w, item(i, false, i)))
+		}
+
+		data := map[string]map[string]interface{}{
+			"s1": {"input": `["a","b","c"]`},
+			"s2": {"reserve": []interface{}{float64(0), float64(1), float64(2)}},
+		}
+		assert.True(t, producer.Has(WORKFLOW_COMPLETED, w.toPayload("1", false, data)))
+	})
+
+	t.Run("only completed items are compensated", func(t *testing.T) {
+		cache := NewCacheMock()
+		producer := NewProducerMock()
+		proc := NewProcessor(cache, producer)
+
+		assert.NoError(t, proc.StartWorkflow(w, "1"))
+		assert.NoError(t, proc.OnComplete(w, item(0, false, nil)))
+		assert.NoError(t, proc.OnFailure(w, item(1, false, nil)))
+
+		// the compensation waits for the item in progress
+		assert.False(t, producer.Has(WORKFLOW_OPERATION_START, item(0, true, items[0])))
+
+		assert.NoError(t, proc.OnComplete(w, item(2, false, nil)))
+		assert.True(t, producer.Has(WORKFLOW_OPERATION_START, item(0, true, items[0])))
+		assert.False(t, producer.Has(WORKFLOW_OPERATION_START, item(1, true, items[1])))
+		assert.True(t, producer.Has(WORKFLOW_OPERATION_START, item(2, true, items[2])))
+
+		assert.NoError(t, proc.OnComplete(w, item(0, true, nil)))
+		assert.NoError(t, proc.OnComplete(w, item(2, true, nil)))
+
+		status, err := GetWorkflowStatus(cache, "1")
+		assert.NoError(t, err)
+		assert.True(t, status.Completed)
+		assert.True(t, status.IsRollback)
+	})
+
+	t.Run("empty array completes at once", func(t *testing.T) {
+		cache := NewCacheMock()
+		producer := NewProducerMock()
+		proc := NewProcessor(cache, producer)
+
+		empty := w
+		empty.Payload = "[]"
+		assert.NoError(t, proc.StartWorkflow(empty, "1"))
+		assert.True(t, producer.Has(WORKFLOW_OPERATION_COMPLETED, reserve.toPayload("1", empty, false, []interface{}{})))
+	})
+
+	t.Run("failed expansion is not retried", func(t *testing.T) {
+		cache := NewCacheMock()
+		producer := NewProducerMock()
+		proc := NewProcessor(cache, producer)
+
+		invalid := w
+		invalid.Operations = []Operation{reserve}
+		invalid.Operations[0].Retry = &RetryPolicy{MaxAttempts: 3}
+		invalid.Payload = "not an array"
+		retried := invalid.Operations[0]
+
+		assert.NoError(t, proc.StartWorkflow(invalid, "1"))
+		failed := retried.toPayload("1", invalid, false, "s1.input is not an array")
+		assert.True(t, producer.Has(WORKFLOW_OPERATION_FAILED, failed))
+
+		// the map operation itself is never sent to an executor
+		assert.NoError(t, proc.OnFailure(invalid, failed))
+		started := retried.toPayload("1", invalid, false, map[string]interface{}{"input": "not an array"})
+		started.setAttempt(2)
+		assert.False(t, producer.Has(WORKFLOW_OPERATION_START, started))
+
+		status, err := GetWorkflowStatus(cache, "1")
+		assert.NoError(t, err)
+		assert.True(t, status.Completed)
+		assert.True(t, status.IsRollback)
+		assert.Equal(t, []OperationStatus{{Operation: retried}}, status.Failed)
+	})
+}
+
+func TestProcessorSubWorkflow(t *testing.T) {
+	cache := NewCacheMock()
+	producer := NewProducerMock()
+	proc := NewProcessor(cache, producer)
+	reserveIDs(t, cache, "1")
+
+	payment, err := NewBuilder("payment").
+		Start("p1").
+		Step("charge", "p1", "p2").
+		End("p2").
+		Build()
+	assert.NoError(t, err)
+
+	payment, err = RegisterDefinition(cache, payment)
+	assert.NoError(t, err)
+
+	w, err := NewBuilder("order").
+		Start("s1").
+		Step("pay", "s1", "s2", WithWorkflow("payment", 0)).
+		Step("ship", "s1", "s2").
+		End("s2").
+		Build()
+	assert.NoError(t, err)
+
+	pay, ship := w.Operations[0], w.Operations[1]
+	charge := payment.Operations[0]
+
+	// the parent operation starts the child workflow
+	assert.NoError(t, proc.StartWorkflow(w, "1"))
+	assert.True(t, producer.Has(WORKFLOW_START, WorkflowPayload{ID: "2", Name: "payment"}))
+
+	child, err := LoadWorkflow(cache, "2")
+	assert.NoError(t, err)
+	assert.Equal(t, &ParentRef{ID: "1", Name: "order", Operation: pay, Attempt: 1}, child.Parent)
+	assert.Equal(t, 1, child.Version)
+
+	assert.NoError(t, proc.StartWorkflow(child, "2"))
+	assert.NoError(t, proc.OnComplete(child, charge.toPayload("2", child, false, "charged")))
+
+	// the completed child completes the parent operation
+	result := map[string]interface{}{"charge": "charged"}
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_COMPLETED, pay.toPayload("1", w, false, result)))
+
+	status, err := GetWorkflowStatus(cache, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"pay": "2"}, status.Children)
+
+	status, err = GetWorkflowStatus(cache, "2")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", status.ParentID)
+
+	// the child workflow is rollbacked with its parent operation
+	assert.NoError(t, proc.OnComplete(w, pay.toPayload("1", w, false, result)))
+	assert.NoError(t, proc.OnFailure(w, ship.toPayload("1", w, false, nil)))
+	assert.True(t, producer.Has(WORKFLOW_START, WorkflowPayload{ID: "2", IsRollback: true, Name: "payment"}))
+
+	assert.NoError(t, proc.Compensate(child, "2"))
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_START, charge.toPayload("2", child, true, map[string]interface{}{"input": map[string]interface{}{"input": nil}})))
+
+	assert.NoError(t, proc.OnComplete(child, charge.toPayload("2", child, true, "refunded")))
+	compensated := map[string]interface{}{"charge": "refunded"}
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_COMPLETED, pay.toPayload("1", w, true, compensated)))
+
+	assert.NoError(t, proc.OnComplete(w, pay.toPayload("1", w, true, compensated)))
+
+	status, err = GetWorkflowStatus(cache, "1")
+	assert.NoError(t, err)
+	assert.True(t, status.Completed)
+	assert.True(t, status.IsRollback)
+}
+
+func TestProcessorSubWorkflowRetry(t *testing.T) {
+	cache := NewCacheMock()
+	producer := NewProducerMock()
+	proc := NewProcessor(cache, producer)
+	reserveIDs(t, cache, "1")
+
+	payment, err := NewBuilder("payment").
+		Start("p1").
+		Step("charge", "p1", "p2").
+		End("p2").
+		Build()
+	assert.NoError(t, err)
+	_, err = RegisterDefinition(cache, payment)
+	assert.NoError(t, err)
+
+	w, err := NewBuilder("order").
+		Start("s1").
+		Step("pay", "s1", "s2", WithWorkflow("payment", 0), WithRetry(RetryPolicy{MaxAttempts: 2})).
+		End("s2").
+		Build()
+	assert.NoError(t, err)
+	pay := w.Operations[0]
+
+	assert.NoError(t, proc.StartWorkflow(w, "1"))
+	assert.True(t, producer.Has(WORKFLOW_START, WorkflowPayload{ID: "2", Name: "payment"}))
+
+	// the retried operation starts a new child workflow instead of overwriting the rollbacked one
+	assert.NoError(t, proc.OnFailure(w, pay.toPayload("1", w, false, nil)))
+	assert.True(t, producer.Has(WORKFLOW_START, WorkflowPayload{ID: "3", Name: "payment"}))
+
+	child, err := LoadWorkflow(cache, "3")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, child.Parent.Attempt)
+
+	status, err := GetWorkflowStatus(cache, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"pay": "3"}, status.Children)
+	assert.Equal(t, map[string][]string{"pay": {"2"}}, status.Abandoned)
+}
+
+func TestProcessorAbandonChild(t *testing.T) {
+	payment, err := NewBuilder("payment").
+		Start("p1").
+		Step("charge", "p1", "p2").
+		End("p2").
+		Build()
+	assert.NoError(t, err)
+
+	w, err := NewBuilder("order").
+		Start("s1").
+		Step("pay", "s1", "s2", WithWorkflow("payment", 0), WithTimeout(time.Second), WithRetry(RetryPolicy{MaxAttempts: 2})).
+		End("s2").
+		Build()
+	assert.NoError(t, err)
+	pay := w.Operations[0]
+
+	start := func(t *testing.T) (Cache, *ProducerMock, Processor, Workflow) {
+		cache := NewCacheMock()
+		producer := NewProducerMock()
+		proc := NewProcessor(cache, producer)
+		reserveIDs(t, cache, "1")
+
+		_, err := RegisterDefinition(cache, payment)
+		assert.NoError(t, err)
+
+		assert.NoError(t, proc.StartWorkflow(w, "1"))
+		child, err := LoadWorkflow(cache, "2")
+		assert.NoError(t, err)
+		assert.NoError(t, proc.StartWorkflow(child, "2"))
+
+		return cache, producer, proc, child
+	}
+
+	timeout := func(t *testing.T, cache Cache, proc Processor) {
+		assert.NoError(t, proc.OnTimeout(w, "1", time.Now().Add(2*time.Second)))
+		failed := pay.toPayload("1", w, false, nil)
+		failed.setAttempt(1)
+		assert.NoError(t, proc.OnFailure(w, failed))
+
+		status, err := GetWorkflowStatus(cache, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"pay": "3"}, status.Children)
+		assert.Equal(t, map[string][]string{"pay": {"2"}}, status.Abandoned)
+	}
+
+	t.Run("running child is cancelled", func(t *testing.T) {
+		cache, producer, proc, child := start(t)
+		timeout(t, cache, proc)
+
+		assert.True(t, producer.Has(WORKFLOW_START, WorkflowPayload{ID: "3", Name: "payment"}))
+		status, err := GetWorkflowStatus(cache, "2")
+		assert.NoError(t, err)
+		assert.True(t, status.IsCancelled)
+
+		// the charge of the abandoned child is compensated and its end is not reported to the parent
+		charge := child.Operations[0]
+		assert.NoError(t, proc.OnComplete(child, charge.toPayload("2", child, false, "charged")))
+		assert.NoError(t, proc.OnComplete(child, charge.toPayload("2", child, true, "refunded")))
+
+		status, err = GetWorkflowStatus(cache, "2")
+		assert.NoError(t, err)
+		assert.True(t, status.Completed)
+		assert.True(t, status.IsRollback)
+
+		failed := pay.toPayload("1", w, false, map[string]interface{}{"charge": "refunded"})
+		failed.setAttempt(1)
+		assert.False(t, producer.Has(WORKFLOW_OPERATION_FAILED, failed))
+	})
+
+	t.Run("child is not started once abandoned", func(t *testing.T) {
+		cache := NewCacheMock()
+		producer := NewProducerMock()
+		proc := NewProcessor(cache, producer)
+		reserveIDs(t, cache, "1")
+
+		_, err := RegisterDefinition(cache, payment)
+		assert.NoError(t, err)
+
+		assert.NoError(t, proc.StartWorkflow(w, "1"))
+		timeout(t, cache, proc)
+
+		child, err := LoadWorkflow(cache, "2")
+		assert.NoError(t, err)
+		assert.NoError(t, proc.StartWorkflow(child, "2"))
+
+		_, err = GetWorkflowStatus(cache, "2")
+		assert.Error(t, err)
+	})
+
+	t.Run("completed child is compensated", func(t *testing.T) {
+		cache, producer, proc, child := start(t)
+
+		// the child completes after the parent operation is timed out
+		charge := child.Operations[0]
+		assert.NoError(t, proc.OnComplete(child, charge.toPayload("2", child, false, "charged")))
+		timeout(t, cache, proc)
+
+		input := map[string]interface{}{"input": map[string]interface{}{"input": nil}}
+		assert.True(t, producer.Has(WORKFLOW_OPERATION_START, charge.toPayload("2", child, true, input)))
+
+		// the late result of the abandoned child does not complete the retried operation
+		completed := pay.toPayload("1", w, false, map[string]interface{}{"charge": "charged"})
+		completed.setAttempt(1)
+		assert.NoError(t, proc.OnComplete(w, completed))
+
+		status, err := GetWorkflowStatus(cache, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, []OperationStatus{{Operation: pay}}, status.InProgress)
+	})
+}
+
+func TestProcessorStuckChild(t *testing.T) {
+	payment, err := NewBuilder("payment").
+		Start("p1").
+		Step("charge", "p1", "p2").
+		End("p2").
+		Build()
+	assert.NoError(t, err)
+
+	// the child whose compensation fails gets stuck at once
+	compensate := func(t *testing.T, w Workflow) (Cache, *ProducerMock, Processor, Workflow) {
+		cache := NewCacheMock()
+		producer := NewProducerMock()
+		proc := NewProcessor(cache, producer)
+		reserveIDs(t, cache, "1")
+
+		_, err := RegisterDefinition(cache, payment)
+		assert.NoError(t, err)
+
+		pay, ship := w.Operations[0], w.Operations[1]
+		assert.NoError(t, proc.StartWorkflow(w, "1"))
+		child, err := LoadWorkflow(cache, "2")
+		assert.NoError(t, err)
+		assert.NoError(t, proc.StartWorkflow(child, "2"))
+
+		charge := child.Operations[0]
+		assert.NoError(t, proc.OnComplete(child, charge.toPayload("2", child, false, "charged")))
+		assert.NoError(t, proc.OnComplete(w, pay.toPayload("1", w, false, map[string]interface{}{"charge": "charged"})))
+		assert.NoError(t, proc.OnFailure(w, ship.toPayload("1", w, false, nil)))
+
+		assert.NoError(t, proc.Compensate(child, "2"))
+		assert.NoError(t, proc.OnFailure(child, charge.toPayload("2", child, true, nil)))
+
+		status, err := GetWorkflowStatus(cache, "2")
+		assert.NoError(t, err)
+		assert.True(t, status.IsStuck)
+
+		// the stuck child fails the compensation of the parent operation
+		failed := pay.toPayload("1", w, true, map[string]interface{}{"charge": "charged"})
+		failed.setAttempt(1)
+		assert.True(t, producer.Has(WORKFLOW_OPERATION_FAILED, failed))
+		assert.NoError(t, proc.OnFailure(w, failed))
+
+		return cache, producer, proc, child
+	}
+
+	t.Run("parent is stuck", func(t *testing.T) {
+		w, err := NewBuilder("order").
+			Start("s1").
+			Step("pay", "s1", "s2", WithWorkflow("payment", 0)).
+			Step("ship", "s1", "s2").
+			End("s2").
+			Build()
+		assert.NoError(t, err)
 
+		cache, _, _, _ := compensate(t, w)
+
+		status, err := GetWorkflowStatus(cache, "1")
+		assert.NoError(t, err)
+		assert.True(t, status.IsStuck)
+		assert.Empty(t, status.InProgress)
+	})
+
+	t.Run("retried compensation redrives the child", func(t *testing.T) {
+		w, err := NewBuilder("order").
+			Start("s1").
+			Step("pay", "s1", "s2", WithWorkflow("payment", 0), WithCompensationRetry(RetryPolicy{MaxAttempts: 2})).
+			Step("ship", "s1", "s2").
+			End("s2").
+			Build()
+		assert.NoError(t, err)
+		pay := w.Operations[0]
+
+		cache, producer, proc, child := compensate(t, w)
+
+		assert.NoError(t, proc.Compensate(child, "2"))
+		status, err := GetWorkflowStatus(cache, "2")
+		assert.NoError(t, err)
+		assert.False(t, status.IsStuck)
+		assert.False(t, status.IsCancelled)
+
+		charge := child.Operations[0]
+		assert.NoError(t, proc.OnComplete(child, charge.toPayload("2", child, true, "refunded")))
+
+		compensated := pay.toPayload("1", w, true, map[string]interface{}{"charge": "refunded"})
+		compensated.setAttempt(2)
+		assert.True(t, producer.Has(WORKFLOW_OPERATION_COMPLETED, compensated))
+		assert.NoError(t, proc.OnComplete(w, compensated))
+
+		status, err = GetWorkflowStatus(cache, "1")
+		assert.NoError(t, err)
+		assert.True(t, status.Completed)
+		assert.True(t, status.IsRollback)
+		assert.False(t, status.IsStuck)
+	})
+}
+
+func TestProcessorCompensateChild(t *testing.T) {
+	cache := NewCacheMock()
+	producer := NewProducerMock()
+	proc := NewProcessor(cache, producer)
+	reserveIDs(t, cache, "1")
+
+	payment, err := NewBuilder("payment").
+		Start("p1").
+		Step("charge", "p1", "p2").
+		End("p2").
+		Build()
+	assert.NoError(t, err)
+	_, err = RegisterDefinition(cache, payment)
+	assert.NoError(t, err)
+
+	w, err := NewBuilder("order").
+		Start("s1").
+		Step("pay", "s1", "s2", WithWorkflow("payment", 0)).
+		Step("refund", "s1", "s2", WithWorkflow("refunds", 0)).
+		Step("ship", "s1", "s2").
+		End("s2").
+		Build()
+	assert.NoError(t, err)
+	pay, refund, ship := w.Operations[0], w.Operations[1], w.Operations[2]
+
+	assert.NoError(t, proc.StartWorkflow(w, "1"))
+
+	// the child of the missing definition is never started, its operation is completed manually
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_FAILED, refund.toPayload("1", w, false, ErrDefinitionNotFound.Error())))
+	assert.NoError(t, proc.ForceComplete(w, "1", "refund", false, "manual"))
+
+	child, err := LoadWorkflow(cache, "2")
+	assert.NoError(t, err)
+	assert.NoError(t, proc.StartWorkflow(child, "2"))
+	charge := child.Operations[0]
+
+	// the parent operation is completed manually while its child is running
+	assert.NoError(t, proc.ForceComplete(w, "1", "pay", false, "manual"))
+	assert.NoError(t, proc.OnFailure(w, ship.toPayload("1", w, false, nil)))
+
+	// the operation without its child workflow is compensated at once
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_COMPLETED, refund.toPayload("1", w, true, nil)))
+	assert.False(t, producer.Has(WORKFLOW_START, WorkflowPayload{ID: "1.refund", IsRollback: true, Name: "refunds"}))
+	assert.True(t, producer.Has(WORKFLOW_START, WorkflowPayload{ID: "2", IsRollback: true, Name: "payment"}))
+
+	// the running child is cancelled by its compensation and reports the compensation once its charge is compensated
+	assert.NoError(t, proc.Compensate(child, "2"))
+	status, err := GetWorkflowStatus(cache, "2")
+	assert.NoError(t, err)
+	assert.True(t, status.IsCancelled)
+
+	assert.NoError(t, proc.OnComplete(child, charge.toPayload("2", child, false, "charged")))
+	assert.NoError(t, proc.OnComplete(child, charge.toPayload("2", child, true, "refunded")))
+	assert.True(t, producer.Has(WORKFLOW_OPERATION_COMPLETED, pay.toPayload("1", w, true, map[string]interface{}{"charge": "refunded"})))
+
+	assert.NoError(t, proc.OnComplete(w, refund.toPayload("1", w, true, nil)))
+	assert.NoError(t, proc.OnComplete(w, pay.toPayload("1", w, true, map[string]interface{}{"charge": "refunded"})))
+
+	status, err = GetWorkflowStatus(cache, "1")
+	assert.NoError(t, err)
+	assert.True(t, status.Completed)
+	assert.True(t, status.IsRollback)
+}
+
+// reserveIDs reserves the ids of the started workflows as the handler does
+func reserveIDs(t *testing.T, cache Cache, ids ...string) {
+	for _, id := range ids {
+		reserved, err := ReserveID(WORKFLOW_INDEX_KEY, cache)
+		assert.NoError(t, err)
+		assert.Equal(t, id, reserved)
+	}
 }
@@ -7,10 +7,20 @@ import (
 )
 
 type producer struct {
+	broker    broker.Broker
+	namespace string
 }
 
-func NewProducer() Producer {
-	return &producer{}
+// NewProducer publishes to the topics of the namespace on the broker, the default broker is used if it is nil
+func NewProducer(b broker.Broker, namespace string) Producer {
+	if b == nil {
+		b = broker.DefaultBroker
+	}
+
+	return &producer{
+		broker:    b,
+		namespace: namespace,
+	}
 }
 
 type Producer interface {
@@ -19,12 +29,21 @@ type Producer interface {
 	SendMessage(topic string, message interface{}) error
 }
 
+// Topic prefixes the topic with the namespace so coordinators of different environments can share the broker
+func Topic(namespace string, topic string) string {
+	if namespace == "" {
+		return topic
+	}
+
+	return namespace + "." + topic
+}
+
 func (p *producer) Init() error {
-	return broker.Init()
+	return p.broker.Init()
 }
 
 func (p *producer) Connect() error {
-	return broker.Connect()
+	return p.broker.Connect()
 }
 
 func (p *producer) SendMessage(topic string, message interface{}) error {
@@ -37,5 +56,5 @@ func (p *producer) SendMessage(topic string, message interface{}) error {
 		Body: body,
 	}
 
-	return broker.Publish(topic, msg)
+	return p.broker.Publish(Topic(p.namespace, topic), msg)
 }
@@ -7,6 +7,8 @@ import (
 
 type ProducerMock struct {
 	messages map[string][]string
+	// Err is returned by SendMessage instead of sending the message if it is set
+	Err error
 }
 
 func NewProducerMock() *ProducerMock {
@@ -24,6 +26,10 @@ func (p *ProducerMock) Connect() error {
 }
 
 func (p *ProducerMock) SendMessage(topic string, message interface{}) error {
+	if p.Err != nil {
+		return p.Err
+	}
+
 	raw, err := json.Marshal(message)
 	if err != nil {
 		return err
@@ -0,0 +1,29 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go-micro.dev/v4/broker"
+)
+
+func TestProducerNamespace(t *testing.T) {
+	b := broker.NewMemoryBroker()
+	assert.NoError(t, b.Connect())
+
+	received := map[string]int{}
+	for _, topic := range []string{WORKFLOW_COMPLETED, Topic("staging", WORKFLOW_COMPLETED), Topic("prod", WORKFLOW_COMPLETED)} {
+		topic := topic
+		_, err := b.Subscribe(topic, func(e broker.Event) error {
+			received[topic]++
+			return nil
+		})
+		assert.NoError(t, err)
+	}
+
+	p := NewProducer(b, "staging")
+	assert.NoError(t, p.SendMessage(WORKFLOW_COMPLETED, WorkflowPayload{ID: "1"}))
+
+	assert.Equal(t, map[string]int{"staging.wfc": 1}, received)
+	assert.Equal(t, WORKFLOW_COMPLETED, Topic("", WORKFLOW_COMPLETED))
+}
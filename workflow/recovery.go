@@ -0,0 +1,107 @@
+package workflow
+
+import (
+	"sort"
+	"time"
+
+	mc "go-micro.dev/v4/cache"
+)
+
+// setStarted starts the attempt of the operation, the attempt is claimed by the coordinator executing it
+func (s *state) setStarted(op Operation, isRollback bool) {
+	key := op.getKey(isRollback)
+	s.Started[key] = time.Now()
+	delete(s.Owners, key)
+}
+
+// isLost detects the operation attempt which has to be started again, the attempt claimed by the alive coordinator is being executed
+func (p *processor) isLost(key string, grace time.Duration, now time.Time) (bool, error) {
+	op := p.state.InProgress[key]
+	// map operations are restarted by their items and child workflows are recovered by themselves
+	if op.isMap() || op.isWorkflow() {
+		return false, nil
+	}
+
+	// the delayed attempt is started by the timer
+	if _, found := p.state.Retries[key]; found {
+		return false, nil
+	}
+
+	if started, found := p.state.Started[key]; found && now.Sub(started) < grace {
+		return false, nil
+	}
+
+	owner, found := p.state.Owners[key]
+	if !found {
+		return true, nil
+	}
+
+	alive, err := isLeaseAlive(p.cache, owner, now)
+	return !alive, err
+}
+
+// Recover resumes the active workflow when the coordinator is started, the operations in progress longer
+// than the grace period are started again unless they are claimed by a coordinator which is still alive
+func (p *processor) Recover(w Workflow, id string, grace time.Duration, now time.Time) error {
+	p.workflow = w
+
+	p.state = state{
+		ID: id,
+	}
+	err := p.state.load(p.cache)
+	if err == mc.ErrKeyNotFound {
+		// the coordinator is stopped before the state of the indexed workflow is saved
+		return setActive(p.cache, id, false)
+	} else if err != nil {
+		return err
+	}
+
+	err = p.relay(relayAll)
+	if err != nil {
+		return err
+	}
+
+	// the stuck workflow waits for the redrive
+	if p.state.Completed || p.state.IsStuck {
+		return setActive(p.cache, id, false)
+	}
+
+	lost := map[string]int{}
+	for key := range p.state.InProgress {
+		isLost, err := p.isLost(key, grace, now)
+		if err != nil {
+			return err
+		}
+
+		if isLost {
+			lost[key] = p.state.Attempts[key]
+		}
+	}
+
+	err = p.commit(func(s *state) {
+		keys := []string{}
+		for key, attempt := range lost {
+			// the attempt is completed or restarted concurrently
+			if _, found := s.InProgress[key]; found && s.Attempts[key] == attempt {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			op := s.InProgress[key]
+			isRollback := key == op.getKey(true)
+			payload := op.toPayload(s.ID, p.workflow, isRollback, s.getInput(op))
+			payload.setAttempt(s.Attempts[key])
+
+			s.Started[key] = now
+			delete(s.Owners, key)
+			s.enqueue(WORKFLOW_OPERATION_START, payload)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.resolve()
+}
@@ -0,0 +1,57 @@
+package workflow
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+type RetryPolicy struct {
+	MaxAttempts    int           `json:"maxAttempts"`
+	InitialBackoff time.Duration `json:"initialBackoff"`
+	Multiplier     float64       `json:"multiplier"`
+	MaxBackoff     time.Duration `json:"maxBackoff"`
+}
+
+// setRetry delays the attempt of the operation, its timeout is started with the attempt
+func (s *state) setRetry(op Operation, isRollback bool, at time.Time) {
+	key := op.getKey(isRollback)
+	s.Retries[key] = at
+	delete(s.Deadlines, key)
+}
+
+// getDueRetries returns the keys of the delayed attempts which are due
+func (s *state) getDueRetries(now time.Time) []string {
+	keys := []string{}
+	for key, at := range s.Retries {
+		if !now.Before(at) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (r *RetryPolicy) canRetry(attempts int) bool {
+	return r != nil && attempts < r.MaxAttempts
+}
+
+// getBackoff returns the delay before the given attempt, the first retry is the attempt 2
+func (r *RetryPolicy) getBackoff(attempt int) time.Duration {
+	if r == nil || attempt < 2 {
+		return 0
+	}
+
+	multiplier := r.Multiplier
+	if multiplier <= 0 {
+		multiplier = 1
+	}
+
+	backoff := time.Duration(float64(r.InitialBackoff) * math.Pow(multiplier, float64(attempt-2)))
+	if r.MaxBackoff > 0 && backoff > r.MaxBackoff {
+		return r.MaxBackoff
+	}
+
+	return backoff
+}
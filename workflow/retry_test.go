@@ -0,0 +1,55 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	var tests = map[string]struct {
+		retry    *RetryPolicy
+		attempts int
+		canRetry bool
+		backoff  time.Duration
+	}{
+		"should not retry without policy": {
+			retry:    nil,
+			attempts: 1,
+			canRetry: false,
+			backoff:  0,
+		},
+		"should retry with initial backoff": {
+			retry:    &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, Multiplier: 2},
+			attempts: 1,
+			canRetry: true,
+			backoff:  time.Second,
+		},
+		"should multiply backoff": {
+			retry:    &RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Second, Multiplier: 2},
+			attempts: 3,
+			canRetry: true,
+			backoff:  4 * time.Second,
+		},
+		"should limit backoff": {
+			retry:    &RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Second, Multiplier: 2, MaxBackoff: 3 * time.Second},
+			attempts: 3,
+			canRetry: true,
+			backoff:  3 * time.Second,
+		},
+		"should stop when attempts are exhausted": {
+			retry:    &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second},
+			attempts: 3,
+			canRetry: false,
+			backoff:  time.Second,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.canRetry, tc.retry.canRetry(tc.attempts))
+			assert.Equal(t, tc.backoff, tc.retry.getBackoff(tc.attempts+1))
+		})
+	}
+}
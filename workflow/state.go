@@ -4,15 +4,58 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
+
+	mc "go-micro.dev/v4/cache"
 )
 
 type state struct {
-	ID         int
+	ID         string
 	IsRollback bool
 	Completed  bool
+	// IsStuck is set when a compensation is failed and retries are exhausted
+	IsStuck    bool
 	Done       map[string]Operation
 	InProgress map[string]Operation
 	Data       map[string]map[string]interface{}
+	Attempts   map[string]int
+	// Deadline is the time the whole workflow is rollbacked at, zero if the workflow has no timeout
+	Deadline  time.Time
+	Deadlines map[string]time.Time
+	Failed    map[string]Operation
+	// Skipped are the operations of the branches not taken
+	Skipped map[string]Operation
+	// Items are the arrays the map operations are expanded over
+	Items map[string][]interface{}
+	// Parent is set for the workflow started by the operation of another workflow
+	Parent *ParentRef
+	// IsCompensation is set when the completed workflow is rollbacked by its parent
+	IsCompensation bool
+	// IsPaused stops spawning the operations until the workflow is continued
+	IsPaused bool
+	// IsCancelled is set when the running workflow is rollbacked on request
+	IsCancelled bool
+	// Settled are the operations completed or skipped by the operator, they are never spawned again
+	Settled map[string]Operation
+	// Children are the ids of the workflows started by the operations
+	Children map[string]string
+	// Abandoned are the ids of the workflows started by the failed attempts of the operations
+	Abandoned map[string][]string
+	// IsAbandoned is set when the parent operation gives up the child workflow, its end is not reported to the parent
+	IsAbandoned bool
+	// Retries are the times the delayed attempts of the operations in progress are started at
+	Retries map[string]time.Time
+	// Started are the times the operations in progress are started at
+	Started map[string]time.Time
+	// Owners are the coordinators executing the operations in progress
+	Owners map[string]string
+	// Processed are the event ids of the applied operation results
+	Processed map[string]bool
+	// Outbox are the messages saved with the state changes and not published yet
+	Outbox    []OutboxMessage
+	OutboxSeq int
+	// err is the error of the change, the state is not saved if it is set
+	err error
 }
 
 func (s *state) getCacheKey() string {
@@ -27,11 +70,27 @@ func (s *state) init(cache Cache, start string, payload interface{}) error {
 	s.Done = make(map[string]Operation)
 	s.InProgress = make(map[string]Operation)
 	s.Data = make(map[string]map[string]interface{})
+	s.Attempts = make(map[string]int)
+	s.Deadlines = make(map[string]time.Time)
+	s.Failed = make(map[string]Operation)
+	s.Skipped = make(map[string]Operation)
+	s.Items = make(map[string][]interface{})
+	s.Children = make(map[string]string)
+	s.Abandoned = make(map[string][]string)
+	s.Processed = make(map[string]bool)
+	s.Started = make(map[string]time.Time)
+	s.Retries = make(map[string]time.Time)
+	s.Owners = make(map[string]string)
+	s.Settled = make(map[string]Operation)
 	s.setData(start, "input", payload)
 
-	key := s.getCacheKey()
-	cache.Set(ctx, key, s)
-	return nil
+	// the workflow is indexed before its state is saved so the recovery does not miss it
+	err := setActive(cache, s.ID, true)
+	if err != nil {
+		return err
+	}
+
+	return cache.Set(ctx, s.getCacheKey(), s)
 }
 
 func (s *state) setData(vertex string, operation string, payload interface{}) {
@@ -44,7 +103,29 @@ func (s *state) setData(vertex string, operation string, payload interface{}) {
 	ops[operation] = payload
 }
 
-func (s *state) update(cache Cache, update func(*state)) error {
+// reset clears the state before it is loaded, the tracers read the reloaded state by its pointer
+func (s *state) reset() {
+	*s = state{
+		ID:         s.ID,
+		Done:       make(map[string]Operation),
+		InProgress: make(map[string]Operation),
+		Data:       make(map[string]map[string]interface{}),
+		Attempts:   make(map[string]int),
+		Deadlines:  make(map[string]time.Time),
+		Failed:     make(map[string]Operation),
+		Skipped:    make(map[string]Operation),
+		Items:      make(map[string][]interface{}),
+		Settled:    make(map[string]Operation),
+		Children:   make(map[string]string),
+		Abandoned:  make(map[string][]string),
+		Retries:    make(map[string]time.Time),
+		Started:    make(map[string]time.Time),
+		Owners:     make(map[string]string),
+		Processed:  make(map[string]bool),
+	}
+}
+
+func (s *state) load(cache Cache) error {
 	ctx := context.Background()
 
 	key := s.getCacheKey()
@@ -54,12 +135,27 @@ func (s *state) update(cache Cache, update func(*state)) error {
 		return err
 	}
 
-	json.Unmarshal([]byte(rawState), s)
-	if err != nil {
-		return err
-	}
+	s.reset()
+	return json.Unmarshal([]byte(rawState), s)
+}
+
+func (s *state) update(cache Cache, update func(*state)) error {
+	return updateValue(cache, s.getCacheKey(), func(raw string, found bool) (interface{}, error) {
+		if !found {
+			return nil, mc.ErrKeyNotFound
+		}
+
+		s.reset()
+		err := json.Unmarshal([]byte(raw), s)
+		if err != nil {
+			return nil, err
+		}
+
+		update(s)
+		if s.err != nil {
+			return nil, s.err
+		}
 
-	update(s)
-	err = cache.Set(ctx, key, s)
-	return err
+		return s, nil
+	})
 }
@@ -0,0 +1,296 @@
+package workflow
+
+import (
+	"fmt"
+	"strconv"
+
+	mc "go-micro.dev/v4/cache"
+)
+
+// ParentRef links the child workflow to the operation of the parent workflow it is started by
+type ParentRef struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Operation Operation `json:"operation"`
+	// Attempt is the attempt of the parent operation the child is started by
+	Attempt int `json:"attempt,omitempty"`
+}
+
+func (op *Operation) isWorkflow() bool {
+	return op.Workflow != ""
+}
+
+// each attempt of the operation starts its own child workflow
+func getChildID(id string, op Operation, attempt int) string {
+	if attempt > 1 {
+		return fmt.Sprintf("%s.%s.%d", id, op.Name, attempt)
+	}
+
+	return fmt.Sprintf("%s.%s", id, op.Name)
+}
+
+// reserveChildID returns the id of the child started by the attempt of the operation,
+// the child of the numeric workflow gets a numeric id so its operations can call sagaproc
+func (p *processor) reserveChildID(s *state, op Operation, attempt int) (string, error) {
+	key := getChildID(s.ID, op, attempt)
+	if _, err := strconv.ParseInt(s.ID, 10, 64); err != nil {
+		return key, nil
+	}
+
+	// the id is reserved once even if the change is retried on a conflict
+	if id, found := p.reserved[key]; found {
+		return id, nil
+	}
+
+	id, err := ReserveID(WORKFLOW_INDEX_KEY, p.cache)
+	if err != nil {
+		return "", err
+	}
+
+	if p.reserved == nil {
+		p.reserved = make(map[string]string)
+	}
+	p.reserved[key] = id
+
+	return id, nil
+}
+
+// spawnWorkflow starts the child workflow of the operation or compensates it on rollback
+func (p *processor) spawnWorkflow(op Operation) error {
+	isRollback := p.state.IsRollback
+
+	return p.commit(func(s *state) {
+		if !s.canSpawn(op, isRollback) {
+			return
+		}
+
+		addOp(s.InProgress, op, isRollback)
+		s.Attempts[op.getKey(isRollback)] = 1
+		s.setDeadline(op, isRollback)
+		s.setStarted(op, isRollback)
+
+		p.startChild(s, op, isRollback)
+	})
+}
+
+func (p *processor) startChild(s *state, op Operation, isRollback bool) {
+	id := s.Children[op.Name]
+
+	if isRollback {
+		started, err := p.isChildStarted(id)
+		if err != nil {
+			s.err = err
+			return
+		}
+
+		// the operation completed without its child, e.g. by ForceCompleteOperation, has nothing to compensate
+		if !started {
+			payload := op.toPayload(s.ID, p.workflow, true, nil)
+			payload.setAttempt(s.Attempts[op.getKey(true)])
+			s.enqueue(WORKFLOW_OPERATION_COMPLETED, payload)
+			return
+		}
+	} else {
+		var err error
+		id, err = p.reserveChildID(s, op, s.Attempts[op.getKey(false)])
+		if err != nil {
+			s.err = err
+			return
+		}
+		s.Children[op.Name] = id
+
+		child, err := GetDefinition(p.cache, op.Workflow, op.WorkflowVersion)
+		if err != nil {
+			// a missing definition fails the operation instead of the coordinator
+			payload := op.toPayload(s.ID, p.workflow, false, err.Error())
+			payload.setAttempt(s.Attempts[op.getKey(false)])
+			s.enqueue(WORKFLOW_OPERATION_FAILED, payload)
+			return
+		}
+
+		child.Payload = s.getInput(op)
+		child.Parent = &ParentRef{
+			ID:        s.ID,
+			Name:      p.workflow.Name,
+			Operation: op,
+			Attempt:   s.Attempts[op.getKey(false)],
+		}
+
+		err = SaveWorkflow(p.cache, id, child)
+		if err != nil {
+			s.err = err
+			return
+		}
+	}
+
+	payload := WorkflowPayload{
+		ID:         id,
+		IsRollback: isRollback,
+		Name:       op.Workflow,
+	}
+
+	s.enqueue(WORKFLOW_START, payload)
+}
+
+func (p *processor) isChildStarted(id string) (bool, error) {
+	if id == "" {
+		return false, nil
+	}
+
+	return hasState(p.cache, id)
+}
+
+// Compensate rollbacks the child workflow when its parent operation is compensated,
+// the running child is cancelled and its operations in progress are compensated once they are completed
+func (p *processor) Compensate(w Workflow, id string) error {
+	p.workflow = w
+
+	p.state = state{
+		ID: id,
+	}
+	err := p.state.update(p.cache, func(s *state) {
+		// the compensation of the parent operation is retried by redriving the failed compensations of the stuck child
+		if s.IsStuck {
+			s.redrive(w)
+		} else if !s.Completed && !s.IsRollback {
+			s.IsCancelled = true
+			s.IsPaused = false
+		}
+
+		s.IsRollback = true
+		s.IsCompensation = true
+		s.Completed = false
+	})
+	if err != nil {
+		return err
+	}
+
+	err = p.cancelChildren()
+	if err != nil {
+		return err
+	}
+
+	err = setActive(p.cache, id, true)
+	if err != nil {
+		return err
+	}
+
+	return p.resolve()
+}
+
+// notifyParent reports the end of the child workflow as the result of the parent operation,
+// the stuck child fails the parent operation
+func (p *processor) notifyParent(s *state) {
+	parent := s.Parent
+	if parent == nil || s.IsAbandoned {
+		return
+	}
+
+	w := Workflow{
+		Name: parent.Name,
+	}
+	payload := parent.Operation.toPayload(parent.ID, w, s.IsCompensation, s.Data[p.workflow.End])
+	if s.IsCompensation {
+		// the compensation of the parent operation may be retried so its current attempt is reported
+		attempt, err := getAttempt(p.cache, parent.ID, parent.Operation.getKey(true))
+		if err != nil {
+			s.err = err
+			return
+		}
+		payload.setAttempt(attempt)
+	} else if parent.Attempt > 0 {
+		payload.setAttempt(parent.Attempt)
+	}
+
+	topic := WORKFLOW_OPERATION_COMPLETED
+	if s.IsStuck || (s.IsRollback && !s.IsCompensation) {
+		topic = WORKFLOW_OPERATION_FAILED
+	}
+
+	s.enqueue(topic, payload)
+}
+
+func getAttempt(cache Cache, id string, key string) (int, error) {
+	s := state{
+		ID: id,
+	}
+	err := s.load(cache)
+	if err != nil {
+		return 0, err
+	}
+
+	if s.Attempts[key] < 1 {
+		return 1, nil
+	}
+
+	return s.Attempts[key], nil
+}
+
+// abandon keeps the id of the child of the failed attempt of the operation
+func (s *state) abandon(op Operation, id string) {
+	if s.Abandoned == nil {
+		s.Abandoned = make(map[string][]string)
+	}
+
+	for _, abandoned := range s.Abandoned[op.Name] {
+		if abandoned == id {
+			return
+		}
+	}
+
+	s.Abandoned[op.Name] = append(s.Abandoned[op.Name], id)
+}
+
+func isAbandoned(cache Cache, parent *ParentRef, id string) (bool, error) {
+	if parent == nil {
+		return false, nil
+	}
+
+	s := state{
+		ID: parent.ID,
+	}
+	err := s.load(cache)
+	if err != nil {
+		return false, err
+	}
+
+	for _, abandoned := range s.Abandoned[parent.Operation.Name] {
+		if abandoned == id {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// abandonChild rollbacks the child workflow of the failed attempt of the operation,
+// the running child is cancelled and the completed one is compensated, its end is not reported to the parent
+func (p *processor) abandonChild(id string) error {
+	s := state{
+		ID: id,
+	}
+	err := s.update(p.cache, func(s *state) {
+		s.IsAbandoned = true
+	})
+	if err == mc.ErrKeyNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if s.IsRollback {
+		return nil
+	}
+
+	child, err := LoadWorkflow(p.cache, id)
+	if err != nil {
+		return err
+	}
+
+	proc := NewProcessor(p.cache, p.producer)
+	if s.Completed {
+		return proc.Compensate(child, id)
+	}
+
+	return proc.Cancel(child, id)
+}
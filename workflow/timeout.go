@@ -0,0 +1,92 @@
+package workflow
+
+import (
+	"sort"
+	"time"
+)
+
+func (s *state) setDeadline(op Operation, isRollback bool) {
+	// compensations are not limited by the operation timeout
+	if op.Timeout > 0 && !isRollback {
+		s.Deadlines[op.getKey(isRollback)] = time.Now().Add(op.Timeout)
+	}
+}
+
+func (s *state) isExpired(now time.Time) bool {
+	return !s.Completed && !s.IsRollback && !s.Deadline.IsZero() && !now.Before(s.Deadline)
+}
+
+func (s *state) getExpired(now time.Time) []Operation {
+	result := []Operation{}
+	if s.Completed {
+		return result
+	}
+
+	keys := []string{}
+	for key, deadline := range s.Deadlines {
+		if _, found := s.InProgress[key]; found && !now.Before(deadline) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		result = append(result, s.InProgress[key])
+	}
+
+	return result
+}
+
+// OnTimeout rollbacks the expired workflow or fails its expired operations and starts the delayed attempts
+func (p *processor) OnTimeout(w Workflow, id string, now time.Time) error {
+	p.workflow = w
+
+	p.state = state{
+		ID: id,
+	}
+	err := p.state.load(p.cache)
+	if err != nil {
+		return err
+	}
+
+	if p.state.isExpired(now) {
+		err := p.state.update(p.cache, func(s *state) {
+			s.IsRollback = true
+		})
+		if err != nil {
+			return err
+		}
+
+		t := createReverseTracer(w, &p.state, p.endWorkflow, p.spawnOperation)
+		return t.resolveWorkflow(w.End)
+	}
+
+	if len(p.state.getExpired(now)) == 0 && len(p.state.getDueRetries(now)) == 0 {
+		return nil
+	}
+
+	return p.commit(func(s *state) {
+		for _, key := range s.getDueRetries(now) {
+			delete(s.Retries, key)
+
+			op, found := s.InProgress[key]
+			if !found {
+				continue
+			}
+
+			isRollback := key == op.getKey(true)
+			s.setDeadline(op, isRollback)
+			s.setStarted(op, isRollback)
+			p.startAttempt(s, op, isRollback)
+		}
+
+		// the deadline is removed so the failure is reported only once
+		for _, op := range s.getExpired(now) {
+			delete(s.Deadlines, op.getKey(false))
+
+			payload := op.toPayload(id, w, false, nil)
+			payload.setAttempt(s.Attempts[op.getKey(false)])
+			s.enqueue(WORKFLOW_OPERATION_FAILED, payload)
+		}
+	})
+}
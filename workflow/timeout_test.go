@@ -0,0 +1,78 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessorTimeout(t *testing.T) {
+	ops := []Operation{
+		{
+			Name:    "op1",
+			From:    "s1",
+			To:      "s2",
+			Timeout: time.Minute,
+		},
+		{
+			Name: "op2",
+			From: "s1",
+			To:   "s2",
+		},
+	}
+
+	t.Run("expired operation is failed", func(t *testing.T) {
+		w := Workflow{
+			Name:       "timeout workflow",
+			Start:      "s1",
+			End:        "s2",
+			Operations: ops,
+		}
+
+		cache := NewCacheMock()
+		producer := NewProducerMock()
+		proc := NewProcessor(cache, producer)
+
+		assert.NoError(t, proc.StartWorkflow(w, "1"))
+
+		op1 := ops[0].toPayload("1", w, false, nil)
+
+		// nothing is expired yet
+		assert.NoError(t, proc.OnTimeout(w, "1", time.Now()))
+		assert.False(t, producer.Has(WORKFLOW_OPERATION_FAILED, op1))
+
+		assert.NoError(t, proc.OnTimeout(w, "1", time.Now().Add(2*time.Minute)))
+		assert.True(t, producer.Has(WORKFLOW_OPERATION_FAILED, op1))
+
+		// the failure is reported once
+		producer = NewProducerMock()
+		proc = NewProcessor(cache, producer)
+		assert.NoError(t, proc.OnTimeout(w, "1", time.Now().Add(3*time.Minute)))
+		assert.False(t, producer.Has(WORKFLOW_OPERATION_FAILED, op1))
+	})
+
+	t.Run("expired workflow is rollbacked", func(t *testing.T) {
+		w := Workflow{
+			Name:       "timeout workflow",
+			Start:      "s1",
+			End:        "s2",
+			Operations: ops,
+			Timeout:    time.Minute,
+		}
+
+		cache := NewCacheMock()
+		producer := NewProducerMock()
+		proc := NewProcessor(cache, producer)
+
+		assert.NoError(t, proc.StartWorkflow(w, "1"))
+		assert.NoError(t, proc.OnComplete(w, ops[1].toPayload("1", w, false, nil)))
+
+		assert.NoError(t, proc.OnTimeout(w, "1", time.Now().Add(2*time.Minute)))
+
+		payload := make(map[string]interface{})
+		payload["input"] = nil
+		op2 := ops[1].toPayload("1", w, true, payload)
+		assert.True(t, producer.Has(WORKFLOW_OPERATION_START, op2))
+	})
+}
@@ -61,6 +61,8 @@ type tracer struct {
 	getNext        func(current string) (ops []Operation, found bool)
 	isProcessed    func(op Operation) bool
 	canBeSpawned   func(op Operation) bool
+	isSkipped      func(current string, op Operation) (bool, error)
+	skipOperation  func(op Operation) error
 	getNextVertex  func(op Operation) string
 	endWorkflow    func() error
 	spawnOperation func(op Operation) error
@@ -68,20 +70,27 @@ type tracer struct {
 
 func createDirectTracer(
 	w Workflow,
-	s state,
+	s *state,
 	endWorkflow func() error,
 	spawnOperation func(op Operation) error,
+	skipOperation func(op Operation) error,
 ) *tracer {
 	from := createRoute(w.Operations, getFrom)
 	to := createRoute(w.Operations, getTo)
 
+	isSkipped := func(op Operation) bool {
+		return hasOp(s.Skipped, op, false)
+	}
+
+	// a skipped branch is joined like a completed one
 	isMatched := func(op Operation) bool {
-		return hasOp(s.Done, op, false)
+		return hasOp(s.Done, op, false) || isSkipped(op)
 	}
 
 	return &tracer{
+		// the paused workflow is not resolved until it is continued
 		isReady: func(current string) bool {
-			return allMatched(current, to, isMatched)
+			return !s.IsPaused && allMatched(current, to, isMatched)
 		},
 		isFinished: func(current string) bool {
 			return current == w.End
@@ -91,11 +100,21 @@ func createDirectTracer(
 			return ops, found
 		},
 		isProcessed: func(op Operation) bool {
-			return hasOp(s.Done, op, false)
+			return hasOp(s.Done, op, false) || isSkipped(op)
 		},
 		canBeSpawned: func(op Operation) bool {
-			return !hasOp(s.InProgress, op, false)
+			return !hasOp(s.InProgress, op, false) && !hasOp(s.Settled, op, false)
 		},
+		isSkipped: func(current string, op Operation) (bool, error) {
+			// the vertex reached only by skipped branches skips all its operations
+			if current != w.Start && allMatched(current, to, isSkipped) {
+				return true, nil
+			}
+
+			isTaken, err := evalCondition(op.Condition, s.Data)
+			return !isTaken, err
+		},
+		skipOperation: skipOperation,
 		getNextVertex: func(op Operation) string {
 			return op.To
 		},
@@ -108,7 +127,7 @@ func createDirectTracer(
 
 func createReverseTracer(
 	w Workflow,
-	s state,
+	s *state,
 	endWorkflow func() error,
 	spawnOperation func(op Operation) error,
 ) *tracer {
@@ -122,7 +141,7 @@ func createReverseTracer(
 
 	return &tracer{
 		isReady: func(current string) bool {
-			return allMatched(current, from, isMatched)
+			return !s.IsPaused && allMatched(current, from, isMatched)
 		},
 		isFinished: func(current string) bool {
 			return current == w.Start
@@ -136,7 +155,14 @@ func createReverseTracer(
 			return !done || (done && hasOp(s.Done, op, true))
 		},
 		canBeSpawned: func(op Operation) bool {
-			return !hasOp(s.InProgress, op, true)
+			return !hasOp(s.InProgress, op, true) && !hasOp(s.Failed, op, true) && !hasOp(s.Settled, op, true)
+		},
+		// skipped operations are never done so there is nothing to compensate
+		isSkipped: func(current string, op Operation) (bool, error) {
+			return false, nil
+		},
+		skipOperation: func(op Operation) error {
+			return nil
 		},
 		getNextVertex: func(op Operation) string {
 			return op.From
@@ -169,8 +195,22 @@ func (t *tracer) resolveWorkflow(current string) error {
 							return err
 						}
 					} else if t.canBeSpawned(op) {
-						// if operation can be spawned do it
-						err := t.spawnOperation(op)
+						skip, err := t.isSkipped(current, op)
+						if err != nil {
+							return err
+						}
+
+						if skip {
+							// if operation is skipped continue resolution of the next vertex as it has been processed
+							err = t.skipOperation(op)
+							if err == nil {
+								err = t.resolveWorkflow(t.getNextVertex(op))
+							}
+						} else {
+							// if operation can be spawned do it
+							err = t.spawnOperation(op)
+						}
+
 						if err != nil {
 							return err
 						}
@@ -1,6 +1,7 @@
 package workflow
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -228,7 +229,11 @@ func TestDirectTracer(t *testing.T) {
 				return nil
 			}
 
-			tracer := createDirectTracer(w, s, end, spawn)
+			skip := func(op Operation) error {
+				return nil
+			}
+
+			tracer := createDirectTracer(w, &s, end, spawn, skip)
 
 			tracer.resolveWorkflow(tc.current)
 			assert.Equal(t, tc.expected, spawned)
@@ -421,7 +426,7 @@ func TestReverseTracer(t *testing.T) {
 				return nil
 			}
 
-			tracer := createReverseTracer(w, s, end, spawn)
+			tracer := createReverseTracer(w, &s, end, spawn)
 
 			tracer.resolveWorkflow(tc.current)
 			assert.Equal(t, tc.expected, spawned)
@@ -429,3 +434,31 @@ func TestReverseTracer(t *testing.T) {
 		})
 	}
 }
+
+func TestTracerReloadedState(t *testing.T) {
+	ops := []Operation{
+		{Name: "op1", From: "s1", To: "s2"},
+		{Name: "op2", From: "s2", To: "s3"},
+	}
+	w := Workflow{Start: "s1", End: "s3", Operations: ops}
+
+	cache := NewCacheMock()
+	saved := state{ID: "1"}
+	saved.reset()
+	addOp(saved.Done, ops[0], false)
+	assert.NoError(t, cache.Set(context.Background(), saved.getCacheKey(), saved))
+
+	spawned := []string{}
+	spawn := func(op Operation) error {
+		spawned = append(spawned, op.Name)
+		return nil
+	}
+
+	// the tracer reads the state loaded after it is created
+	s := state{ID: "1"}
+	tracer := createDirectTracer(w, &s, func() error { return nil }, spawn, func(op Operation) error { return nil })
+	assert.NoError(t, s.load(cache))
+
+	assert.NoError(t, tracer.resolveWorkflow(w.Start))
+	assert.Equal(t, []string{"op2"}, spawned)
+}
@@ -0,0 +1,222 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	ISSUE_EMPTY_NAME         = "empty_name"
+	ISSUE_EMPTY_VERTEX       = "empty_vertex"
+	ISSUE_DUPLICATE_NAME     = "duplicate_name"
+	ISSUE_START_IS_END       = "start_is_end"
+	ISSUE_CYCLE              = "cycle"
+	ISSUE_UNREACHABLE_END    = "unreachable_end"
+	ISSUE_UNREACHABLE_VERTEX = "unreachable_vertex"
+	ISSUE_DEAD_END_VERTEX    = "dead_end_vertex"
+	ISSUE_INVALID_CONDITION  = "invalid_condition"
+	ISSUE_INVALID_FOR_EACH   = "invalid_for_each"
+	ISSUE_CONFLICTING_TYPE   = "conflicting_type"
+	ISSUE_UNKNOWN_TYPE       = "unknown_type"
+	ISSUE_EMPTY_SERVICE      = "empty_service"
+	ISSUE_RECURSIVE_WORKFLOW = "recursive_workflow"
+)
+
+type ValidationIssue struct {
+	Code      string `json:"code"`
+	Operation string `json:"operation,omitempty"`
+	Vertex    string `json:"vertex,omitempty"`
+	Message   string `json:"message"`
+}
+
+type ValidationError struct {
+	Issues []ValidationIssue `json:"issues"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := []string{}
+	for _, issue := range e.Issues {
+		messages = append(messages, issue.Message)
+	}
+
+	return fmt.Sprintf("invalid workflow: %s", strings.Join(messages, "; "))
+}
+
+func (e *ValidationError) add(code, operation, vertex, format string, a ...interface{}) {
+	e.Issues = append(e.Issues, ValidationIssue{
+		Code:      code,
+		Operation: operation,
+		Vertex:    vertex,
+		Message:   fmt.Sprintf(format, a...),
+	})
+}
+
+// Validate rejects workflows which can not be resolved by the tracers
+func Validate(w Workflow) error {
+	e := &ValidationError{}
+
+	if w.Start == "" {
+		e.add(ISSUE_EMPTY_VERTEX, "", "", "start vertex is empty")
+	}
+	if w.End == "" {
+		e.add(ISSUE_EMPTY_VERTEX, "", "", "end vertex is empty")
+	}
+	if w.Start != "" && w.Start == w.End {
+		e.add(ISSUE_START_IS_END, "", w.Start, "start and end vertices are the same %s vertex", w.Start)
+	}
+
+	names := make(map[string]bool)
+	for i, op := range w.Operations {
+		if op.Name == "" {
+			e.add(ISSUE_EMPTY_NAME, "", "", "operation %d has an empty name", i)
+		} else if names[op.Name] {
+			e.add(ISSUE_DUPLICATE_NAME, op.Name, "", "operation %s is duplicated", op.Name)
+		}
+		names[op.Name] = true
+
+		if op.From == "" || op.To == "" {
+			e.add(ISSUE_EMPTY_VERTEX, op.Name, "", "operation %s has an empty vertex", op.Name)
+		}
+
+		if op.Condition != "" {
+			if _, err := parseCondition(op.Condition); err != nil {
+				e.add(ISSUE_INVALID_CONDITION, op.Name, "", "operation %s has an invalid condition: %v", op.Name, err)
+			}
+		}
+
+		if op.ForEach != "" {
+			if _, err := parseItems(op.ForEach); err != nil {
+				e.add(ISSUE_INVALID_FOR_EACH, op.Name, "", "operation %s has an invalid items path: %v", op.Name, err)
+			}
+		}
+
+		if op.ForEach != "" && op.Workflow != "" {
+			e.add(ISSUE_CONFLICTING_TYPE, op.Name, "", "operation %s can not be both a map and a workflow operation", op.Name)
+		}
+
+		if op.isWorkflow() && op.Workflow == w.Name {
+			e.add(ISSUE_RECURSIVE_WORKFLOW, op.Name, "", "operation %s starts its own workflow %s", op.Name, w.Name)
+		}
+
+		if op.Type == EXECUTOR_HTTP && op.Workflow == "" && op.Service == "" {
+			e.add(ISSUE_EMPTY_SERVICE, op.Name, "", "http operation %s has no service url", op.Name)
+		}
+	}
+
+	// graph checks make sense only for a well formed workflow
+	if len(e.Issues) == 0 {
+		validateGraph(w, e)
+	}
+
+	if len(e.Issues) > 0 {
+		return e
+	}
+
+	return nil
+}
+
+func validateGraph(w Workflow, e *ValidationError) {
+	from := createRoute(w.Operations, getFrom)
+	to := createRoute(w.Operations, getTo)
+
+	if cycle := findCycle(w, from); len(cycle) > 0 {
+		e.add(ISSUE_CYCLE, "", cycle[0], "workflow has a cycle %s", strings.Join(cycle, " -> "))
+		return
+	}
+
+	reachable := collectVertices(w.Start, from, getTo)
+	if !reachable[w.End] {
+		e.add(ISSUE_UNREACHABLE_END, "", w.End, "end vertex %s is not reachable from start vertex %s", w.End, w.Start)
+		return
+	}
+
+	productive := collectVertices(w.End, to, getFrom)
+	for _, vertex := range getVertices(w) {
+		if !reachable[vertex] {
+			e.add(ISSUE_UNREACHABLE_VERTEX, "", vertex, "vertex %s is not reachable from start vertex %s", vertex, w.Start)
+		} else if !productive[vertex] {
+			e.add(ISSUE_DEAD_END_VERTEX, "", vertex, "end vertex %s is not reachable from vertex %s", w.End, vertex)
+		}
+	}
+}
+
+func getVertices(w Workflow) []string {
+	vertices := make(map[string]bool)
+	for _, op := range w.Operations {
+		vertices[op.From] = true
+		vertices[op.To] = true
+	}
+
+	result := []string{}
+	for vertex := range vertices {
+		result = append(result, vertex)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+func collectVertices(current string, relation route, getNextVertex getOperationKey) map[string]bool {
+	result := make(map[string]bool)
+
+	var visit func(current string)
+	visit = func(current string) {
+		if result[current] {
+			return
+		}
+
+		result[current] = true
+		for _, op := range relation[current] {
+			visit(getNextVertex(op))
+		}
+	}
+	visit(current)
+
+	return result
+}
+
+func findCycle(w Workflow, from route) []string {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	marks := make(map[string]int)
+	path := []string{}
+
+	var visit func(current string) []string
+	visit = func(current string) []string {
+		switch marks[current] {
+		case visiting:
+			// the cycle is the tail of the path started at the current vertex
+			for i, vertex := range path {
+				if vertex == current {
+					return append(append([]string{}, path[i:]...), current)
+				}
+			}
+		case visited:
+			return nil
+		}
+
+		marks[current] = visiting
+		path = append(path, current)
+		for _, op := range from[current] {
+			if cycle := visit(op.To); len(cycle) > 0 {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		marks[current] = visited
+
+		return nil
+	}
+
+	for _, vertex := range getVertices(w) {
+		if cycle := visit(vertex); len(cycle) > 0 {
+			return cycle
+		}
+	}
+
+	return nil
+}
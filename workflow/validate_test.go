@@ -0,0 +1,111 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	var tests = map[string]struct {
+		start      string
+		end        string
+		operations []Operation
+		expected   []string
+	}{
+		"should accept valid workflow": {
+			start: "s1",
+			end:   "s2",
+			operations: []Operation{
+				{Name: "op1", From: "s1", To: "s2"},
+				{Name: "op2", From: "s1", To: "s3"},
+				{Name: "op3", From: "s3", To: "s2"},
+			},
+			expected: []string{},
+		},
+		"should reject same start and end": {
+			start: "s1",
+			end:   "s1",
+			operations: []Operation{
+				{Name: "op1", From: "s1", To: "s2"},
+			},
+			expected: []string{ISSUE_START_IS_END},
+		},
+		"should reject empty and duplicate names": {
+			start: "s1",
+			end:   "s2",
+			operations: []Operation{
+				{Name: "op1", From: "s1", To: "s2"},
+				{Name: "op1", From: "s1", To: "s3"},
+				{Name: "", From: "s3", To: "s2"},
+			},
+			expected: []string{ISSUE_DUPLICATE_NAME, ISSUE_EMPTY_NAME},
+		},
+		"should reject empty vertices": {
+			start: "",
+			end:   "s2",
+			operations: []Operation{
+				{Name: "op1", From: "s1", To: ""},
+			},
+			expected: []string{ISSUE_EMPTY_VERTEX, ISSUE_EMPTY_VERTEX},
+		},
+		"should reject cycle": {
+			start: "s1",
+			end:   "s4",
+			operations: []Operation{
+				{Name: "op1", From: "s1", To: "s2"},
+				{Name: "op2", From: "s2", To: "s3"},
+				{Name: "op3", From: "s3", To: "s2"},
+				{Name: "op4", From: "s3", To: "s4"},
+			},
+			expected: []string{ISSUE_CYCLE},
+		},
+		"should reject unreachable end": {
+			start: "s1",
+			end:   "s3",
+			operations: []Operation{
+				{Name: "op1", From: "s1", To: "s2"},
+			},
+			expected: []string{ISSUE_UNREACHABLE_END},
+		},
+		"should reject dangling vertices": {
+			start: "s1",
+			end:   "s2",
+			operations: []Operation{
+				{Name: "op1", From: "s1", To: "s2"},
+				{Name: "op2", From: "s1", To: "s3"},
+				{Name: "op3", From: "s4", To: "s2"},
+			},
+			expected: []string{ISSUE_DEAD_END_VERTEX, ISSUE_UNREACHABLE_VERTEX},
+		},
+		"should reject http without service": {
+			start: "s1",
+			end:   "s2",
+			operations: []Operation{
+				{Name: "op1", From: "s1", To: "s2", Type: EXECUTOR_HTTP},
+				{Name: "op2", From: "s1", To: "s2", Type: EXECUTOR_HTTP, Service: "http://localhost/op2"},
+			},
+			expected: []string{ISSUE_EMPTY_SERVICE},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := Workflow{
+				Start:      tc.start,
+				End:        tc.end,
+				Operations: tc.operations,
+			}
+
+			codes := []string{}
+			err := Validate(w)
+			if err != nil {
+				for _, issue := range err.(*ValidationError).Issues {
+					codes = append(codes, issue.Code)
+				}
+			}
+
+			assert.Equal(t, tc.expected, codes)
+		})
+	}
+}
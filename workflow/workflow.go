@@ -1,6 +1,11 @@
 package workflow
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
 	pb "github.com/awe76/sagawf/proto"
 )
 
@@ -10,9 +15,16 @@ type Workflow struct {
 	End        string      `json:"end"`
 	Operations []Operation `json:"operations"`
 	Payload    interface{} `json:"payload"`
+	// Timeout rollbacks the whole workflow if it is not completed in time
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// Version is the version of the definition the workflow is started from
+	Version  int               `json:"version,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Parent is set for the child workflow of the operation
+	Parent *ParentRef `json:"parent,omitempty"`
 }
 
-func (w *Workflow) toPayload(id int, isReversion bool, data map[string]map[string]interface{}) WorkflowPayload {
+func (w *Workflow) toPayload(id string, isReversion bool, data map[string]map[string]interface{}) WorkflowPayload {
 	return WorkflowPayload{
 		ID:         id,
 		Name:       w.Name,
@@ -21,6 +33,28 @@ func (w *Workflow) toPayload(id int, isReversion bool, data map[string]map[strin
 	}
 }
 
+func getWorkflowKey(id string) string {
+	return fmt.Sprintf("workflow:definition:%s", id)
+}
+
+func SaveWorkflow(cache Cache, id string, w Workflow) error {
+	ctx := context.Background()
+	return cache.Set(ctx, getWorkflowKey(id), w)
+}
+
+func LoadWorkflow(cache Cache, id string) (Workflow, error) {
+	ctx := context.Background()
+
+	var w Workflow
+	raw, err := cache.Get(ctx, getWorkflowKey(id))
+	if err != nil {
+		return w, err
+	}
+
+	err = json.Unmarshal([]byte(raw), &w)
+	return w, err
+}
+
 func ToWorkflow(req *pb.WorkflowRequest) Workflow {
 	return Workflow{
 		Name:       req.Name,
@@ -28,6 +62,19 @@ func ToWorkflow(req *pb.WorkflowRequest) Workflow {
 		End:        req.End,
 		Operations: toOperations(req.Operations),
 		Payload:    req.Payload,
+		Timeout:    time.Duration(req.TimeoutMs) * time.Millisecond,
+	}
+}
+
+func FromDefinition(def *pb.WorkflowDefinition) Workflow {
+	return Workflow{
+		Name:       def.Name,
+		Start:      def.Start,
+		End:        def.End,
+		Operations: toOperations(def.Operations),
+		Timeout:    time.Duration(def.TimeoutMs) * time.Millisecond,
+		Version:    int(def.Version),
+		Metadata:   def.Metadata,
 	}
 }
 
@@ -35,11 +82,34 @@ func toOperations(ops []*pb.Operation) []Operation {
 	result := []Operation{}
 	for _, op := range ops {
 		result = append(result, Operation{
-			Name: op.Name,
-			From: op.From,
-			To:   op.To,
+			Name:    op.Name,
+			From:    op.From,
+			To:      op.To,
+			Retry:   toRetryPolicy(op.Retry),
+			Timeout: time.Duration(op.TimeoutMs) * time.Millisecond,
+
+			CompensationRetry: toRetryPolicy(op.CompensationRetry),
+			Condition:         op.Condition,
+			ForEach:           op.ForEach,
+			Workflow:          op.Workflow,
+			WorkflowVersion:   int(op.WorkflowVersion),
+			Type:              op.Type,
+			Service:           op.Service,
 		})
 	}
 
 	return result
 }
+
+func toRetryPolicy(r *pb.RetryPolicy) *RetryPolicy {
+	if r == nil {
+		return nil
+	}
+
+	return &RetryPolicy{
+		MaxAttempts:    int(r.MaxAttempts),
+		InitialBackoff: time.Duration(r.InitialBackoffMs) * time.Millisecond,
+		Multiplier:     r.Multiplier,
+		MaxBackoff:     time.Duration(r.MaxBackoffMs) * time.Millisecond,
+	}
+}
@@ -1,8 +1,9 @@
 package workflow
 
 type WorkflowPayload struct {
-	ID         int
+	ID         string
 	IsRollback bool
+	IsStuck    bool
 	Name       string
 	Data       map[string]map[string]interface{}
 }
@@ -0,0 +1,84 @@
+package workflow
+
+import (
+	"sort"
+	"strings"
+)
+
+type OperationStatus struct {
+	Operation  Operation
+	IsRollback bool
+}
+
+type WorkflowStatus struct {
+	ID          string
+	IsRollback  bool
+	Completed   bool
+	IsStuck     bool
+	IsPaused    bool
+	IsCancelled bool
+	Done        []OperationStatus
+	InProgress  []OperationStatus
+	Failed      []OperationStatus
+	Skipped     []OperationStatus
+	Settled     []OperationStatus
+	ParentID    string
+	Children    map[string]string
+	Abandoned   map[string][]string
+	Data        map[string]map[string]interface{}
+}
+
+func GetWorkflowStatus(cache Cache, id string) (WorkflowStatus, error) {
+	s := state{
+		ID: id,
+	}
+
+	err := s.load(cache)
+	if err != nil {
+		return WorkflowStatus{}, err
+	}
+
+	return WorkflowStatus{
+		ID:          s.ID,
+		IsRollback:  s.IsRollback,
+		Completed:   s.Completed,
+		IsStuck:     s.IsStuck,
+		IsPaused:    s.IsPaused,
+		IsCancelled: s.IsCancelled,
+		Done:        toOperationStatuses(s.Done),
+		InProgress:  toOperationStatuses(s.InProgress),
+		Failed:      toOperationStatuses(s.Failed),
+		Skipped:     toOperationStatuses(s.Skipped),
+		Settled:     toOperationStatuses(s.Settled),
+		ParentID:    getParentID(s.Parent),
+		Children:    s.Children,
+		Abandoned:   s.Abandoned,
+		Data:        s.Data,
+	}, nil
+}
+
+func getParentID(parent *ParentRef) string {
+	if parent == nil {
+		return ""
+	}
+
+	return parent.ID
+}
+
+func toOperationStatuses(m map[string]Operation) []OperationStatus {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := []OperationStatus{}
+	for _, key := range keys {
+		result = append(result, OperationStatus{
+			Operation:  m[key],
+			IsRollback: strings.HasSuffix(key, ":true"),
+		})
+	}
+
+	return result
+}